@@ -1,18 +1,127 @@
 package api
 
 import (
-	_ "embed"
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
 	"fmt"
+	"html/template"
+	"io/fs"
+	"mime"
 	"net/http"
+	"path"
+	"time"
 )
 
 //go:embed openapi.gen.json
 var embeddedJSONSpec []byte
 
+//go:embed openapi.gen.yml
+var embeddedYAMLSpec []byte
+
+// embeddedDocs contains the documentation page template and the Swagger UI assets it loads
+//
+//go:embed docs
+var embeddedDocs embed.FS
+
+// embeddedFile is a static file served with an ETag computed from its content. Clients
+// must revalidate on every use, so a new deployment is picked up immediately.
+type embeddedFile struct {
+	name        string
+	contentType string
+	content     []byte
+	etag        string
+}
+
+func newEmbeddedFile(name, contentType string, content []byte) embeddedFile {
+	sum := sha256.Sum256(content)
+	return embeddedFile{
+		name:        name,
+		contentType: contentType,
+		content:     content,
+		etag:        fmt.Sprintf(`"%s"`, hex.EncodeToString(sum[:16])),
+	}
+}
+
+func (f embeddedFile) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", f.contentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("ETag", f.etag)
+	// handles If-None-Match (304) and HEAD requests
+	http.ServeContent(w, r, f.name, time.Time{}, bytes.NewReader(f.content))
+}
+
+var (
+	jsonSpecFile = newEmbeddedFile("openapi.json", "application/json", embeddedJSONSpec)
+	yamlSpecFile = newEmbeddedFile("openapi.yaml", "application/yaml", embeddedYAMLSpec)
+	docsAssets   = newDocsAssets()
+)
+
+// docsPage is the template of the documentation page, it is not served as an asset
+const docsPage = "index.html"
+
+func newDocsAssets() map[string]embeddedFile {
+	files := newEmbeddedDir(embeddedDocs, "docs")
+	delete(files, docsPage)
+	return files
+}
+
+// newEmbeddedDir creates embedded files of the directory by their names, content types are
+// guessed from the extensions.
+func newEmbeddedDir(fsys fs.FS, dir string) map[string]embeddedFile {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		panic(fmt.Errorf("cannot read embedded directory %s: %w", dir, err))
+	}
+
+	files := make(map[string]embeddedFile, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		content, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			panic(fmt.Errorf("cannot read embedded file %s: %w", name, err))
+		}
+		files[name] = newEmbeddedFile(name, mime.TypeByExtension(path.Ext(name)), content)
+	}
+	return files
+}
+
+// ServeOpenAPISpec writes the embedded spec in JSON
 func ServeOpenAPISpec(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	if _, err := w.Write(embeddedJSONSpec); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		_, _ = w.Write([]byte(fmt.Sprintf(`{"msg": "%s"`, err.Error())))
+	jsonSpecFile.ServeHTTP(w, r)
+}
+
+// ServeOpenAPISpecYAML writes the embedded spec in YAML
+func ServeOpenAPISpecYAML(w http.ResponseWriter, r *http.Request) {
+	yamlSpecFile.ServeHTTP(w, r)
+}
+
+// NewDocsHandler returns the handler of the API documentation page rendered by the embedded
+// Swagger UI. The spec and the assets are loaded from absolute paths under the prefix
+// (e.g. /api/template/v1), so the page works at /docs and /docs/. It does not load any
+// external resources and works offline.
+func NewDocsHandler(prefix string) http.Handler {
+	tmpl := template.Must(template.ParseFS(embeddedDocs, path.Join("docs", docsPage)))
+	data := struct{ SpecURL, AssetsURL string }{
+		SpecURL:   prefix + "/openapi.json",
+		AssetsURL: prefix + "/docs",
+	}
+
+	var page bytes.Buffer
+	if err := tmpl.Execute(&page, data); err != nil {
+		panic(fmt.Errorf("cannot render documentation page: %w", err))
+	}
+	return newEmbeddedFile(docsPage, "text/html; charset=utf-8", page.Bytes())
+}
+
+// ServeDocsAsset writes a file loaded by the documentation page, e.g. docs/swagger-ui-bundle.js.
+// The file is identified by the last element of the path.
+func ServeDocsAsset(w http.ResponseWriter, r *http.Request) {
+	file, ok := docsAssets[path.Base(r.URL.Path)]
+	if !ok {
+		http.NotFound(w, r)
+		return
 	}
+	file.ServeHTTP(w, r)
 }
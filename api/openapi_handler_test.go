@@ -0,0 +1,91 @@
+package api_test
+
+import (
+	"consoledot-go-template/api"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeEmbeddedFiles(t *testing.T) {
+	handlers := map[string]http.HandlerFunc{
+		"application/json":         api.ServeOpenAPISpec,
+		"application/yaml":         api.ServeOpenAPISpecYAML,
+		"text/html; charset=utf-8": api.NewDocsHandler("/api/template/v1").ServeHTTP,
+	}
+
+	for contentType, handler := range handlers {
+		t.Run(contentType, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			handler(rr, httptest.NewRequest("GET", "/", nil))
+
+			require.Equal(t, http.StatusOK, rr.Code, "Wrong status code")
+			assert.Equal(t, contentType, rr.Header().Get("Content-Type"))
+			assert.Equal(t, "no-cache", rr.Header().Get("Cache-Control"))
+			assert.NotEmpty(t, rr.Body.String())
+
+			etag := rr.Header().Get("ETag")
+			require.NotEmpty(t, etag)
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("If-None-Match", etag)
+			rr = httptest.NewRecorder()
+			handler(rr, req)
+
+			assert.Equal(t, http.StatusNotModified, rr.Code, "Wrong status code")
+			assert.Empty(t, rr.Body.String())
+		})
+	}
+
+	t.Run("docs page loads spec and assets under the prefix", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		api.NewDocsHandler("/api/template/v1").ServeHTTP(rr, httptest.NewRequest("GET", "/api/template/v1/docs/", nil))
+
+		require.Equal(t, http.StatusOK, rr.Code, "Wrong status code")
+		assert.Contains(t, rr.Body.String(), `url: "/api/template/v1/openapi.json"`)
+		assert.Contains(t, rr.Body.String(), `src="/api/template/v1/docs/swagger-ui-bundle.js"`)
+		assert.Contains(t, rr.Body.String(), `href="/api/template/v1/docs/swagger-ui.css"`)
+	})
+
+	t.Run("docs assets", func(t *testing.T) {
+		assets := map[string]string{
+			"/api/template/v1/docs/swagger-ui-bundle.js": "javascript",
+			"/api/template/v1/docs/swagger-ui.css":       "text/css",
+		}
+		for path, contentType := range assets {
+			rr := httptest.NewRecorder()
+			api.ServeDocsAsset(rr, httptest.NewRequest("GET", path, nil))
+
+			require.Equal(t, http.StatusOK, rr.Code, "Wrong status code of %s", path)
+			assert.Contains(t, rr.Header().Get("Content-Type"), contentType)
+			assert.Equal(t, "no-cache", rr.Header().Get("Cache-Control"))
+			assert.NotEmpty(t, rr.Header().Get("ETag"))
+			assert.NotEmpty(t, rr.Body.String())
+		}
+	})
+
+	t.Run("docs page template is not an asset", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		api.ServeDocsAsset(rr, httptest.NewRequest("GET", "/api/template/v1/docs/index.html", nil))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code, "Wrong status code")
+	})
+
+	t.Run("unknown docs asset", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		api.ServeDocsAsset(rr, httptest.NewRequest("GET", "/docs/missing.js", nil))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code, "Wrong status code")
+	})
+
+	t.Run("different content has different ETag", func(t *testing.T) {
+		jsonRR, yamlRR := httptest.NewRecorder(), httptest.NewRecorder()
+		api.ServeOpenAPISpec(jsonRR, httptest.NewRequest("GET", "/", nil))
+		api.ServeOpenAPISpecYAML(yamlRR, httptest.NewRequest("GET", "/", nil))
+
+		assert.NotEqual(t, jsonRR.Header().Get("ETag"), yamlRR.Header().Get("ETag"))
+	})
+}
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Spec parses the embedded OpenAPI spec. Every call returns a new copy
+// which can be modified by the caller.
+func Spec() (*openapi3.T, error) {
+	spec, err := openapi3.NewLoader().LoadFromData(embeddedJSONSpec)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load embedded OpenAPI spec: %w", err)
+	}
+	return spec, nil
+}
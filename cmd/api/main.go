@@ -3,8 +3,11 @@ package main
 import (
 	"consoledot-go-template/internal/config"
 	"consoledot-go-template/internal/db"
+	"consoledot-go-template/internal/health"
+	"consoledot-go-template/internal/lifecycle"
 	"consoledot-go-template/internal/logging"
 	"consoledot-go-template/internal/routes"
+	"consoledot-go-template/internal/telemetry"
 	// DAO import for pgx implementation
 	_ "consoledot-go-template/internal/dao/pgx"
 
@@ -13,8 +16,8 @@ import (
 	"fmt"
 	"net/http"
 	"os"
-	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/rs/zerolog/log"
 )
@@ -23,42 +26,110 @@ func main() {
 	mainCtx := context.Background()
 	config.Initialize("config/api.env")
 
-	logger, closeFn := logging.InitializeLogger()
-	defer closeFn()
+	logger, closeLogger := logging.InitializeLogger()
 	log.Logger = logger
 
+	// hooks run by phases, so they can be registered as soon as the resources are created
+	lc := lifecycle.NewManager(config.Application.ShutdownTimeout)
+	lc.OnShutdown(lifecycle.PhaseFlush, "logger", 0, func(_ context.Context) error {
+		closeLogger()
+		return nil
+	})
+
+	closeTelemetry, err := telemetry.Initialize(mainCtx)
+	if err != nil {
+		lc.Fatal(err, "Error initializing telemetry")
+	}
+	lc.OnShutdown(lifecycle.PhaseFlush, "telemetry", 0, func(_ context.Context) error {
+		closeTelemetry()
+		return nil
+	})
+
 	// initialize the rest
-	err := db.Initialize(mainCtx, "public")
+	err = db.Initialize(mainCtx, "public")
 	if err != nil {
-		log.Fatal().Err(err).Msg("Error initializing database")
-		panic(err)
+		lc.Fatal(err, "Error initializing database")
 	}
-	defer db.Close()
+	lc.OnShutdown(lifecycle.PhaseClose, "database", 0, func(_ context.Context) error {
+		db.Close()
+		return nil
+	})
+	db.RegisterHealthChecks()
 
-	//log.Info().Msgf("Starting an instance on port %d with prometheus on %d", config.Application.Port, config.Prometheus.Port)
-	log.Info().Msgf("Starting an instance on port %d", config.Application.Port)
-	router := routes.RootRouter()
-	apiServer := http.Server{
-		Addr:    fmt.Sprintf(":%d", config.Application.Port),
-		Handler: router,
+	// refuse serving with an outdated schema, migrations are applied by the init container of any pod
+	if config.Database.MigrationWait {
+		err = db.WaitForMigrations(mainCtx, "public", config.Database.MigrationWaitTimeout)
+		if err != nil {
+			lc.Fatal(err, "Error waiting for database migrations")
+		}
 	}
+	if config.Database.SchemaCheck != db.SchemaCheckOff {
+		if _, err = db.CheckSchemaVersion(mainCtx); err != nil {
+			switch {
+			case errors.Is(err, db.ErrSchemaAhead):
+				log.Warn().Err(err).Msg("Database schema was migrated by a newer version of the service")
+			case errors.Is(err, db.ErrSchemaBehind) && config.Database.SchemaCheck == db.SchemaCheckReadiness:
+				log.Error().Err(err).Msg("Database schema is outdated, the service is not ready until it is migrated")
+			default:
+				lc.Fatal(err, "Incompatible database schema")
+			}
+		}
+	}
+
+	log.Info().Msgf("Starting an instance on port %d with prometheus on %d", config.Application.Port, config.Prometheus.Port)
+	router := routes.RootRouter()
+	apiServer := newServer(config.Application.Port, router)
+
+	metricsRouter := routes.MetricsRouter()
+	metricsServer := newServer(config.Prometheus.Port, metricsRouter)
+
+	lc.OnShutdown(lifecycle.PhaseStopAccepting, "readiness", 0, func(ctx context.Context) error {
+		// fail readiness probe first so the platform stops routing new requests
+		health.SetShuttingDown()
+		if lc.Signal() != syscall.SIGTERM || config.Application.DrainDelay <= 0 {
+			return nil
+		}
+		log.Info().Msgf("Waiting %s for the traffic to drain", config.Application.DrainDelay)
+		select {
+		case <-time.After(config.Application.DrainDelay):
+		case <-ctx.Done():
+		}
+		return nil
+	})
+	lc.OnShutdown(lifecycle.PhaseDrain, "api server", config.Application.DrainTimeout, apiServer.Shutdown)
+	lc.OnShutdown(lifecycle.PhaseDrain, "metrics server", config.Application.DrainTimeout, metricsServer.Shutdown)
 
-	waitForSignal := make(chan struct{})
 	go func() {
-		sigs := make(chan os.Signal, 1)
-		signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
-		<-sigs
-		if err := apiServer.Shutdown(context.Background()); err != nil {
-			log.Fatal().Err(err).Msg("Main service shutdown error")
+		if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			lc.Fatal(err, "Metrics service listen error")
 		}
-		close(waitForSignal)
 	}()
-
-	if err := apiServer.ListenAndServe(); err != nil {
-		if !errors.Is(err, http.ErrServerClosed) {
-			log.Fatal().Err(err).Msg("Main service listen error")
+	go func() {
+		var err error
+		if config.TLSEnabled() {
+			err = apiServer.ListenAndServeTLS(config.Server.TLSCertFile, config.Server.TLSKeyFile)
+		} else {
+			err = apiServer.ListenAndServe()
 		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			lc.Fatal(err, "Main service listen error")
+		}
+	}()
+
+	if err := lc.WaitForSignal(); err != nil {
+		os.Exit(1)
 	}
+}
 
-	<-waitForSignal
+// newServer returns HTTP server with timeouts and limits set by config
+func newServer(port int, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           handler,
+		ReadTimeout:       config.Server.ReadTimeout,
+		ReadHeaderTimeout: config.Server.ReadHeaderTimeout,
+		WriteTimeout:      config.Server.WriteTimeout,
+		IdleTimeout:       config.Server.IdleTimeout,
+		MaxHeaderBytes:    config.Server.MaxHeaderBytes,
+	}
 }
@@ -3,8 +3,17 @@ package main
 import (
 	"consoledot-go-template/internal/config"
 	"consoledot-go-template/internal/db"
+	"consoledot-go-template/internal/db/migrations"
 	"consoledot-go-template/internal/logging"
 	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/rs/zerolog/log"
 )
@@ -13,24 +22,159 @@ import (
 //	random.SeedGlobal()
 //}
 
+var (
+	ErrDownInClowder  = errors.New("reverting migrations in clowder environment")
+	ErrUnknownCommand = errors.New("unknown command")
+	ErrInvalidFlag    = errors.New("invalid flag")
+	ErrLintFailed     = errors.New("migrations have lint errors")
+)
+
+const usage = `Usage: migrate [command] [flags]
+
+Commands:
+  up [--to N] [--dry-run]
+                    apply pending migrations, up to version N (default all), --dry-run prints
+                    the SQL and applies it in a transaction which is rolled back
+  down [--to N]     revert migrations down to version N (default the last one)
+  redo              revert the last migration and apply it again
+  status            print applied and pending migrations
+  lint              check embedded migrations, warnings are printed but do not fail
+
+Commands reverting migrations are refused in clowder environment unless --allow-clowder is given.
+The up command is run when no command is given.
+`
+
+// options of the migrate command
+type options struct {
+	command      string
+	to           int32
+	dryRun       bool
+	allowClowder bool
+}
+
 func main() {
 	ctx := context.Background()
 	config.Initialize("config/api.env", "config/migrate.env")
 
 	// initialize stdout logging and AWS clients first (cloudwatch is not available in init containers)
 	logger, closeFn := logging.InitializeLogger()
-	defer closeFn()
 	log.Logger = logger
 
-	err := db.Initialize(ctx, "public")
+	opts, err := parseArgs(os.Args[1:])
+	if err != nil {
+		closeFn()
+		log.Fatal().Err(err).Msg("Invalid arguments")
+	}
+
+	// linting only reads the embedded files, it does not need the database
+	if opts.command == "lint" {
+		err = lint(os.Stdout)
+		closeFn()
+		if err != nil {
+			log.Fatal().Err(err).Msg("Migration lint failed")
+		}
+		return
+	}
+
+	err = db.Initialize(ctx, "public")
 	if err != nil {
+		closeFn()
 		log.Fatal().Err(err).Msg("Error initializing database")
 	}
-	defer db.Close()
 
-	err = db.Migrate(ctx, "public")
+	err = run(ctx, opts)
+	db.Close()
+	closeFn()
 	if err != nil {
 		logger.Fatal().Err(err).Msg("Error running migration")
-		return
 	}
 }
+
+func parseArgs(args []string) (options, error) {
+	opts := options{command: "up"}
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		opts.command, args = args[0], args[1:]
+	}
+
+	flags := flag.NewFlagSet(opts.command, flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(flags.Output(), usage) }
+	to := flags.Int("to", -1, "target version")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "print and verify pending migrations without applying them")
+	flags.BoolVar(&opts.allowClowder, "allow-clowder", false, "allow reverting migrations in clowder environment")
+	if err := flags.Parse(args); err != nil {
+		return opts, fmt.Errorf("cannot parse flags: %w", err)
+	}
+	opts.to = int32(*to)
+
+	switch opts.command {
+	case "up", "down", "redo", "status", "lint":
+	default:
+		flags.Usage()
+		return opts, fmt.Errorf("%w: %s", ErrUnknownCommand, opts.command)
+	}
+	if opts.dryRun && opts.command != "up" {
+		return opts, fmt.Errorf("%w: dry run is only supported by the up command", ErrInvalidFlag)
+	}
+	if (opts.command == "down" || opts.command == "redo") && config.InClowder() && !opts.allowClowder {
+		return opts, fmt.Errorf("%w: use --allow-clowder to override", ErrDownInClowder)
+	}
+	return opts, nil
+}
+
+func run(ctx context.Context, opts options) error {
+	switch opts.command {
+	case "down":
+		return db.MigrateDown(ctx, "public", opts.to)
+	case "redo":
+		return db.MigrateRedo(ctx, "public")
+	case "status":
+		return printStatus(ctx)
+	default:
+		if opts.dryRun {
+			return db.MigrateDryRun(ctx, "public", opts.to, os.Stdout)
+		}
+		return db.MigrateUp(ctx, "public", opts.to)
+	}
+}
+
+// lint prints issues of embedded migrations and fails when any of them is an error
+func lint(w io.Writer) error {
+	issues, err := db.LintMigrations(migrations.EmbeddedSQLMigrations)
+	if err != nil {
+		return fmt.Errorf("cannot lint migrations: %w", err)
+	}
+
+	errorCount := 0
+	for _, issue := range issues {
+		fmt.Fprintln(w, issue)
+		if issue.Severity == db.LintError {
+			errorCount++
+		}
+	}
+	if errorCount > 0 {
+		return fmt.Errorf("%w: %d of %d issues", ErrLintFailed, errorCount, len(issues))
+	}
+	return nil
+}
+
+func printStatus(ctx context.Context) error {
+	current, statuses, err := db.MigrationStatuses(ctx, "public")
+	if err != nil {
+		return fmt.Errorf("cannot read migration status: %w", err)
+	}
+
+	fmt.Printf("Current version: %d of %d\n\n", current, len(statuses))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT\tREVERSIBLE")
+	for _, s := range statuses {
+		status, appliedAt := "pending", ""
+		if s.Applied {
+			status = "applied"
+		}
+		if s.AppliedAt != nil {
+			appliedAt = s.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%t\n", s.Version, s.Name, status, appliedAt, s.Reversible)
+	}
+	return w.Flush()
+}
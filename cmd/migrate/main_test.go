@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseArgs(t *testing.T) {
+	t.Run("up by default", func(t *testing.T) {
+		opts, err := parseArgs(nil)
+		require.NoError(t, err)
+		assert.Equal(t, options{command: "up", to: -1}, opts)
+	})
+
+	t.Run("command with target version", func(t *testing.T) {
+		opts, err := parseArgs([]string{"down", "--to", "2"})
+		require.NoError(t, err)
+		assert.Equal(t, options{command: "down", to: 2}, opts)
+	})
+
+	t.Run("flags without command", func(t *testing.T) {
+		opts, err := parseArgs([]string{"--to=3"})
+		require.NoError(t, err)
+		assert.Equal(t, options{command: "up", to: 3}, opts)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		opts, err := parseArgs([]string{"up", "--dry-run"})
+		require.NoError(t, err)
+		assert.Equal(t, options{command: "up", to: -1, dryRun: true}, opts)
+
+		_, err = parseArgs([]string{"down", "--dry-run"})
+		assert.ErrorIs(t, err, ErrInvalidFlag)
+	})
+
+	t.Run("reverting in clowder", func(t *testing.T) {
+		t.Setenv("ACG_CONFIG", "/cdapp/cdappconfig.json")
+
+		for _, command := range []string{"down", "redo"} {
+			_, err := parseArgs([]string{command})
+			assert.ErrorIs(t, err, ErrDownInClowder, command)
+
+			opts, err := parseArgs([]string{command, "--allow-clowder"})
+			require.NoError(t, err, command)
+			assert.Equal(t, options{command: command, to: -1, allowClowder: true}, opts)
+		}
+
+		_, err := parseArgs([]string{"up"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("lint", func(t *testing.T) {
+		opts, err := parseArgs([]string{"lint"})
+		require.NoError(t, err)
+		assert.Equal(t, options{command: "lint", to: -1}, opts)
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		_, err := parseArgs([]string{"sideways"})
+		assert.ErrorIs(t, err, ErrUnknownCommand)
+	})
+}
+
+func TestLint(t *testing.T) {
+	var out strings.Builder
+	require.NoError(t, lint(&out))
+	assert.Empty(t, out.String())
+}
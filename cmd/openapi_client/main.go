@@ -0,0 +1,415 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const (
+	specPath   = "./api/openapi.gen.json"
+	outputPath = "./pkg/client/client.gen.go"
+)
+
+//go:embed client.go.tmpl
+var clientTemplate string
+
+type goField struct {
+	Name     string
+	Type     string
+	JSONName string
+	Required bool
+	Comment  string
+}
+
+type goType struct {
+	Name    string
+	Comment string
+	Fields  []goField
+}
+
+type goParam struct {
+	Name    string
+	Type    string
+	Param   string
+	Comment string
+}
+
+type goOperation struct {
+	Name          string
+	OperationID   string
+	Method        string
+	Path          string
+	Comment       string
+	PathParams    []goParam
+	QueryParams   []goParam
+	RequestType   string
+	ResponseType  string
+	SuccessStatus string
+}
+
+// ParamsType is the name of the struct holding query parameters
+func (op goOperation) ParamsType() string {
+	return op.Name + "Params"
+}
+
+// PathExpr is Go expression building the path from path parameters
+func (op goOperation) PathExpr() string {
+	expr := strconv.Quote(op.Path)
+	for _, param := range op.PathParams {
+		placeholder := "{" + param.Param + "}"
+		expr = strings.Replace(expr, placeholder, `" + pathParam(`+param.Name+`) + "`, 1)
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(expr, `"" + `), ` + ""`)
+}
+
+type generator struct {
+	spec       *openapi3.T
+	types      map[string]*goType
+	operations []goOperation
+}
+
+func main() {
+	check := flag.Bool("check", false, "verify the generated client is up to date instead of writing it")
+	flag.Parse()
+
+	spec, err := openapi3.NewLoader().LoadFromFile(specPath)
+	if err != nil {
+		panic(err)
+	}
+
+	source, err := generate(spec)
+	if err != nil {
+		panic(err)
+	}
+
+	if *check {
+		committed, err := os.ReadFile(outputPath)
+		if err != nil || !bytes.Equal(committed, source) {
+			fmt.Printf("The generated client %s is outdated, run 'make generate-openapi'\n", outputPath)
+			os.Exit(1)
+		}
+		return
+	}
+	if err = os.WriteFile(outputPath, source, 0o644); err != nil {
+		panic(err)
+	}
+}
+
+// generate returns formatted source of the client types and operations
+func generate(spec *openapi3.T) ([]byte, error) {
+	g := &generator{spec: spec, types: make(map[string]*goType)}
+
+	for _, name := range sortedKeys(spec.Components.Schemas) {
+		if _, err := g.namedType(typeName(name), spec.Components.Schemas[name].Value); err != nil {
+			return nil, fmt.Errorf("schema %s: %w", name, err)
+		}
+		if typ := g.types[typeName(name)]; typ.Comment == "" {
+			typ.Comment = fmt.Sprintf("is the %s schema.", name)
+		}
+	}
+	for _, path := range sortedKeys(spec.Paths) {
+		pathItem := spec.Paths[path]
+		for _, method := range sortedKeys(pathItem.Operations()) {
+			op, err := g.operation(path, method, pathItem)
+			if err != nil {
+				return nil, fmt.Errorf("operation %s %s: %w", method, path, err)
+			}
+			g.operations = append(g.operations, op)
+		}
+	}
+
+	types := make([]*goType, 0, len(g.types))
+	for _, name := range sortedKeys(g.types) {
+		types = append(types, g.types[name])
+	}
+
+	tmpl, err := template.New("client").Funcs(template.FuncMap{"comment": comment}).Parse(clientTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse template: %w", err)
+	}
+	// imports used by query parameters
+	usesFmt, usesURL := false, false
+	for _, op := range g.operations {
+		for _, param := range op.QueryParams {
+			usesURL = true
+			usesFmt = usesFmt || param.Type != "string"
+		}
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, map[string]interface{}{
+		"Title":      spec.Info.Title,
+		"Version":    spec.Info.Version,
+		"Types":      types,
+		"Operations": g.operations,
+		"UsesFmt":    usesFmt,
+		"UsesURL":    usesURL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot execute template: %w", err)
+	}
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("cannot format generated code: %w\n%s", err, buf.String())
+	}
+	return source, nil
+}
+
+func (g *generator) operation(path, method string, pathItem *openapi3.PathItem) (goOperation, error) {
+	operation := pathItem.GetOperation(method)
+	if operation.OperationID == "" {
+		return goOperation{}, fmt.Errorf("missing operation ID")
+	}
+	op := goOperation{
+		Name:        goName(operation.OperationID),
+		OperationID: operation.OperationID,
+		Method:      method,
+		Path:        path,
+		Comment:     strings.TrimSpace(operation.Description),
+	}
+
+	params := append(openapi3.Parameters{}, pathItem.Parameters...)
+	params = append(params, operation.Parameters...)
+	for _, paramRef := range params {
+		param := paramRef.Value
+		goParam := goParam{
+			Name:    lowerFirst(goName(param.Name)),
+			Type:    g.scalarType(param.Schema.Value),
+			Param:   param.Name,
+			Comment: param.Description,
+		}
+		switch param.In {
+		case openapi3.ParameterInPath:
+			op.PathParams = append(op.PathParams, goParam)
+		case openapi3.ParameterInQuery:
+			goParam.Name = goName(param.Name)
+			op.QueryParams = append(op.QueryParams, goParam)
+		default:
+			return goOperation{}, fmt.Errorf("parameters in %s are not supported", param.In)
+		}
+	}
+
+	if operation.RequestBody != nil {
+		schema := jsonSchema(operation.RequestBody.Value.Content)
+		if schema == nil {
+			return goOperation{}, fmt.Errorf("only JSON request body is supported")
+		}
+		typ, err := g.schemaType(op.Name+"Request", schema)
+		if err != nil {
+			return goOperation{}, err
+		}
+		op.RequestType = typ
+	}
+
+	for _, status := range sortedKeys(operation.Responses) {
+		code, err := strconv.Atoi(status)
+		if err != nil || code < 200 || code >= 300 {
+			continue
+		}
+		op.SuccessStatus = strconv.Itoa(code)
+		if constant, ok := statusConstants[code]; ok {
+			op.SuccessStatus = "http." + constant
+		}
+		if schema := jsonSchema(operation.Responses[status].Value.Content); schema != nil {
+			if op.ResponseType, err = g.schemaType(op.Name+"Response", schema); err != nil {
+				return goOperation{}, err
+			}
+		}
+		break
+	}
+	if op.SuccessStatus == "" {
+		return goOperation{}, fmt.Errorf("missing success response")
+	}
+	return op, nil
+}
+
+// schemaType returns Go type of the schema, objects are generated as named types
+// named by their component or by the given name when they are inline.
+func (g *generator) schemaType(name string, ref *openapi3.SchemaRef) (string, error) {
+	if ref.Ref != "" {
+		return typeName(strings.TrimPrefix(ref.Ref, "#/components/schemas/")), nil
+	}
+	schema := ref.Value
+	switch schema.Type {
+	case "array":
+		item, err := g.schemaType(name+"Item", schema.Items)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + item, nil
+	case "object", "":
+		// inline objects equal to a component (openapi3gen inlines nested structs) reuse its type
+		if component := g.findComponent(schema); component != "" {
+			return component, nil
+		}
+		return g.namedType(name, schema)
+	default:
+		return g.scalarType(schema), nil
+	}
+}
+
+func (g *generator) namedType(name string, schema *openapi3.Schema) (string, error) {
+	if _, ok := g.types[name]; ok {
+		return name, nil
+	}
+	typ := &goType{Name: name, Comment: strings.TrimSpace(schema.Description)}
+	g.types[name] = typ
+
+	for _, propName := range sortedKeys(schema.Properties) {
+		prop := schema.Properties[propName]
+		fieldName := goName(propName)
+		fieldType, err := g.schemaType(name+fieldName, prop)
+		if err != nil {
+			return "", fmt.Errorf("property %s: %w", propName, err)
+		}
+		if inline, ok := g.types[strings.TrimLeft(fieldType, "[]")]; ok && inline.Comment == "" {
+			inline.Comment = fmt.Sprintf("is the %s property of %s.", propName, name)
+		}
+		if prop.Value != nil && prop.Value.Nullable {
+			fieldType = "*" + fieldType
+		}
+		typ.Fields = append(typ.Fields, goField{
+			Name:     fieldName,
+			Type:     fieldType,
+			JSONName: propName,
+			Required: contains(schema.Required, propName),
+			Comment:  strings.TrimSpace(prop.Value.Description),
+		})
+	}
+	return name, nil
+}
+
+func (g *generator) findComponent(schema *openapi3.Schema) string {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return ""
+	}
+	for _, name := range sortedKeys(g.spec.Components.Schemas) {
+		component, err := json.Marshal(g.spec.Components.Schemas[name].Value)
+		if err == nil && bytes.Equal(data, component) {
+			return typeName(name)
+		}
+	}
+	return ""
+}
+
+func (g *generator) scalarType(schema *openapi3.Schema) string {
+	switch schema.Type {
+	case "integer":
+		if schema.Format == "int32" {
+			return "int32"
+		}
+		return "int64"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "string":
+		return "string"
+	default:
+		return "interface{}"
+	}
+}
+
+func jsonSchema(content openapi3.Content) *openapi3.SchemaRef {
+	if mediaType := content.Get("application/json"); mediaType != nil {
+		return mediaType.Schema
+	}
+	return nil
+}
+
+// typeName converts component name to Go type name, e.g. v1.HelloResponse to HelloResponse
+func typeName(component string) string {
+	if i := strings.LastIndex(component, "."); i >= 0 {
+		component = component[i+1:]
+	}
+	return goName(component)
+}
+
+var initialisms = map[string]string{"id": "ID", "url": "URL", "http": "HTTP", "json": "JSON", "api": "API"}
+
+// goName converts JSON and operation names to exported Go names, e.g. request_id to RequestID
+func goName(name string) string {
+	var sb strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' || r == '.' }) {
+		if initialism, ok := initialisms[strings.ToLower(part)]; ok {
+			sb.WriteString(initialism)
+			continue
+		}
+		sb.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return sb.String()
+}
+
+func lowerFirst(name string) string {
+	if initialism, ok := initialisms[strings.ToLower(name)]; ok && initialism == name {
+		return strings.ToLower(name)
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// commentWidth is the maximum width of generated comment lines
+const commentWidth = 100
+
+// comment formats text as a Go doc comment starting with the name, e.g. "Returns a greeting."
+// of GetHello results in "// GetHello returns a greeting.". Empty text results in an empty string.
+func comment(name, text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ""
+	}
+	if len(text) > 1 && unicode.IsUpper(rune(text[0])) && !unicode.IsUpper(rune(text[1])) {
+		text = strings.ToLower(text[:1]) + text[1:]
+	}
+
+	var sb strings.Builder
+	line := "//"
+	for _, word := range strings.Fields(name + " " + text) {
+		if len(line)+1+len(word) > commentWidth && line != "//" {
+			sb.WriteString(line + "\n")
+			line = "//"
+		}
+		line += " " + word
+	}
+	sb.WriteString(line + "\n")
+	return sb.String()
+}
+
+var statusConstants = map[int]string{
+	http.StatusOK:        "StatusOK",
+	http.StatusCreated:   "StatusCreated",
+	http.StatusAccepted:  "StatusAccepted",
+	http.StatusNoContent: "StatusNoContent",
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
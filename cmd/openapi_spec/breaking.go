@@ -0,0 +1,201 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// direction of the payload, the same change can break clients in one direction only
+type direction int
+
+const (
+	request direction = iota
+	response
+)
+
+type changeReport struct {
+	changes []string
+}
+
+func (r *changeReport) add(location, format string, args ...interface{}) {
+	r.changes = append(r.changes, fmt.Sprintf("%s: %s", location, fmt.Sprintf(format, args...)))
+}
+
+// breakingChanges lists changes of the current spec which can break clients of the previous spec:
+// removed paths and operations, removed parameters and fields, newly required parameters
+// and request fields and changed types.
+func breakingChanges(previous, current *openapi3.T) []string {
+	report := &changeReport{}
+	for _, path := range sortedKeys(previous.Paths) {
+		currentItem := current.Paths.Find(path)
+		if currentItem == nil {
+			report.add(path, "path was removed")
+			continue
+		}
+
+		operations := previous.Paths[path].Operations()
+		for _, method := range sortedKeys(operations) {
+			location := fmt.Sprintf("%s %s", method, path)
+			currentOperation := currentItem.GetOperation(method)
+			if currentOperation == nil {
+				report.add(location, "operation was removed")
+				continue
+			}
+			report.compareOperation(location,
+				parameters(previous.Paths[path], operations[method]), operations[method],
+				parameters(currentItem, currentOperation), currentOperation)
+		}
+	}
+	return report.changes
+}
+
+func (r *changeReport) compareOperation(location string,
+	previousParams openapi3.Parameters, previous *openapi3.Operation,
+	currentParams openapi3.Parameters, current *openapi3.Operation,
+) {
+	for _, paramRef := range previousParams {
+		param := paramRef.Value
+		paramLocation := fmt.Sprintf("%s: %s parameter %s", location, param.In, param.Name)
+		currentParam := currentParams.GetByInAndName(param.In, param.Name)
+		if currentParam == nil {
+			r.add(paramLocation, "parameter was removed")
+			continue
+		}
+		if currentParam.Required && !param.Required {
+			r.add(paramLocation, "parameter is newly required")
+		}
+		r.compareSchema(paramLocation, request, param.Schema, currentParam.Schema)
+	}
+	for _, paramRef := range currentParams {
+		param := paramRef.Value
+		if param.Required && previousParams.GetByInAndName(param.In, param.Name) == nil {
+			r.add(fmt.Sprintf("%s: %s parameter %s", location, param.In, param.Name), "new parameter is required")
+		}
+	}
+
+	previousBody, currentBody := requestBody(previous), requestBody(current)
+	switch {
+	case previousBody == nil && currentBody != nil && currentBody.Required:
+		r.add(location, "request body is newly required")
+	case previousBody != nil && currentBody == nil:
+		r.add(location, "request body was removed")
+	case previousBody != nil && currentBody != nil:
+		if currentBody.Required && !previousBody.Required {
+			r.add(location, "request body is newly required")
+		}
+		r.compareSchema(location+": request body", request, jsonSchema(previousBody.Content), jsonSchema(currentBody.Content))
+	}
+
+	for _, status := range sortedKeys(previous.Responses) {
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+		responseLocation := fmt.Sprintf("%s: response %s", location, status)
+		currentResponse := current.Responses.Get(statusCode(status))
+		if currentResponse == nil || currentResponse.Value == nil {
+			r.add(responseLocation, "response was removed")
+			continue
+		}
+		if previousResponse := previous.Responses[status].Value; previousResponse != nil {
+			r.compareSchema(responseLocation, response, jsonSchema(previousResponse.Content), jsonSchema(currentResponse.Value.Content))
+		}
+	}
+}
+
+func (r *changeReport) compareSchema(location string, dir direction, previous, current *openapi3.SchemaRef) {
+	if previous == nil || previous.Value == nil {
+		return
+	}
+	if current == nil || current.Value == nil {
+		r.add(location, "schema was removed")
+		return
+	}
+	prev, cur := previous.Value, current.Value
+
+	if prev.Type != cur.Type || prev.Format != cur.Format {
+		r.add(location, "type changed from %s to %s", typeName(prev), typeName(cur))
+		return
+	}
+
+	for _, name := range sortedKeys(prev.Properties) {
+		fieldLocation := fmt.Sprintf("%s: field %s", location, name)
+		currentField, ok := cur.Properties[name]
+		if !ok {
+			r.add(fieldLocation, "field was removed")
+			continue
+		}
+		r.compareSchema(fieldLocation, dir, prev.Properties[name], currentField)
+	}
+
+	if dir == request {
+		for _, name := range cur.Required {
+			if !contains(prev.Required, name) {
+				r.add(fmt.Sprintf("%s: field %s", location, name), "field is newly required")
+			}
+		}
+	}
+
+	if prev.Items != nil {
+		r.compareSchema(location+"[]", dir, prev.Items, cur.Items)
+	}
+}
+
+// parameters returns parameters of the operation including parameters of the path,
+// which can be overridden by the operation
+func parameters(pathItem *openapi3.PathItem, operation *openapi3.Operation) openapi3.Parameters {
+	result := append(openapi3.Parameters{}, operation.Parameters...)
+	for _, paramRef := range pathItem.Parameters {
+		if operation.Parameters.GetByInAndName(paramRef.Value.In, paramRef.Value.Name) == nil {
+			result = append(result, paramRef)
+		}
+	}
+	return result
+}
+
+func requestBody(operation *openapi3.Operation) *openapi3.RequestBody {
+	if operation.RequestBody == nil {
+		return nil
+	}
+	return operation.RequestBody.Value
+}
+
+func jsonSchema(content openapi3.Content) *openapi3.SchemaRef {
+	if mediaType := content.Get("application/json"); mediaType != nil {
+		return mediaType.Schema
+	}
+	return nil
+}
+
+func typeName(schema *openapi3.Schema) string {
+	if schema.Format != "" {
+		return fmt.Sprintf("%s (%s)", schema.Type, schema.Format)
+	}
+	return schema.Type
+}
+
+func statusCode(status string) int {
+	var code int
+	_, _ = fmt.Sscanf(status, "%d", &code)
+	return code
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
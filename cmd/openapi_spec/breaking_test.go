@@ -0,0 +1,124 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const previousSpec = `
+openapi: 3.0.0
+info: {title: test, version: 1.0.0}
+paths:
+  /items:
+    get:
+      parameters:
+        - {name: limit, in: query, schema: {type: integer}}
+        - {name: filter, in: query, schema: {type: string}}
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  type: object
+                  properties:
+                    id: {type: integer, format: int64}
+                    name: {type: string}
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name: {type: string}
+                note: {type: string}
+      responses:
+        '200': {description: ok}
+  /legacy:
+    get:
+      responses:
+        '200': {description: ok}
+`
+
+const currentSpec = `
+openapi: 3.0.0
+info: {title: test, version: 1.0.0}
+paths:
+  /items:
+    get:
+      parameters:
+        - {name: limit, in: query, schema: {type: string}}
+        - {name: tenant, in: query, required: true, schema: {type: string}}
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  type: object
+                  properties:
+                    id: {type: integer, format: int64}
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name, note]
+              properties:
+                name: {type: string}
+                note: {type: string}
+                tag: {type: string}
+      responses:
+        '201': {description: created}
+`
+
+func loadSpec(t *testing.T, data string) *openapi3.T {
+	t.Helper()
+	spec, err := openapi3.NewLoader().LoadFromData([]byte(data))
+	require.NoError(t, err, "failed to load spec")
+	return spec
+}
+
+func TestBreakingChanges(t *testing.T) {
+	t.Run("no changes", func(t *testing.T) {
+		assert.Empty(t, breakingChanges(loadSpec(t, previousSpec), loadSpec(t, previousSpec)))
+	})
+
+	t.Run("all kinds of changes", func(t *testing.T) {
+		changes := breakingChanges(loadSpec(t, previousSpec), loadSpec(t, currentSpec))
+
+		assert.Equal(t, []string{
+			"GET /items: query parameter limit: type changed from integer to string",
+			"GET /items: query parameter filter: parameter was removed",
+			"GET /items: query parameter tenant: new parameter is required",
+			"GET /items: response 200[]: field name: field was removed",
+			"POST /items: request body: field note: field is newly required",
+			"POST /items: response 200: response was removed",
+			"/legacy: path was removed",
+		}, changes)
+	})
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Run("equal", func(t *testing.T) {
+		assert.Empty(t, unifiedDiff("spec", []byte("a\nb\n"), []byte("a\nb\n")))
+	})
+
+	t.Run("changed line", func(t *testing.T) {
+		old := []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n")
+		new := []byte("1\n2\n3\n4\n5\nsix\n7\n8\n9\n10\n")
+
+		assert.Equal(t, "--- spec (committed)\n+++ spec (generated)\n"+
+			"@@ -3,7 +3,7 @@\n 3\n 4\n 5\n-6\n+six\n 7\n 8\n 9\n", unifiedDiff("spec", old, new))
+	})
+}
@@ -1,46 +1,199 @@
 package main
 
 import (
+	"bytes"
 	"consoledot-go-template/internal/payloads"
+	"consoledot-go-template/internal/routes"
+	"consoledot-go-template/internal/validation"
 	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
 	"os"
 	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/openapi3gen"
 	"gopkg.in/yaml.v3"
 )
 
-// addPayloads helps isolate all the schema types registration
-func addPayloads(spec *APISpec) {
-	// payloads - MAKE SURE THE TYPE HAS JSON/YAML Go STRUCT TAGS (or "map key XXX not found" error occurs)
-	spec.addTypeSchema("v1.HelloRequest", &payloads.HelloRequest{})
-	spec.addTypeSchema("v1.HelloResponse", &payloads.HelloResponse{})
+// addPaths generates paths from the API route descriptors, payload schemas of
+// the routes are registered as components named by their type.
+func addPaths(spec *APISpec) {
+	for _, rt := range routes.APIRoutes() {
+		operation := openapi3.NewOperation()
+		operation.OperationID = rt.OperationID
+		operation.Description = rt.Description
+		operation.Responses = openapi3.Responses{}
+		for _, param := range rt.Parameters {
+			operation.Parameters = append(operation.Parameters, &openapi3.ParameterRef{Value: param})
+		}
+
+		if rt.Request != nil {
+			operation.RequestBody = &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().
+				WithDescription("The request payload format").
+				WithRequired(true).
+				WithJSONSchemaRef(spec.addPayloadSchema(rt.Request))}
+		}
+
+		response := openapi3.NewResponse().WithDescription(rt.ResponseDescription)
+		if rt.Response != nil {
+			response = response.WithJSONSchemaRef(spec.addPayloadSchema(rt.Response))
+		}
+		operation.AddResponse(rt.SuccessStatus(), response)
+
+		for _, status := range rt.ErrorStatuses() {
+			name, ok := errorResponses[status]
+			if !ok {
+				panic(fmt.Sprintf("operation %s: no error response for status %d", rt.OperationID, status))
+			}
+			operation.Responses[strconv.Itoa(status)] = &openapi3.ResponseRef{Ref: "#/components/responses/" + name}
+		}
+
+		path := specPath(rt.Path)
+		if spec.Paths[path] == nil {
+			spec.Paths[path] = &openapi3.PathItem{}
+		}
+		if spec.Paths[path].GetOperation(rt.Method) != nil {
+			panic(fmt.Sprintf("duplicate operation %s %s", rt.Method, rt.Path))
+		}
+		spec.Paths[path].SetOperation(rt.Method, operation)
+	}
+}
+
+// chiParamRegexp matches chi path parameters with a regular expression, e.g. {id:[0-9]+}
+var chiParamRegexp = regexp.MustCompile(`{([^:}]+):[^}]+}`)
+
+// specPath converts chi route pattern to OpenAPI path template
+func specPath(pattern string) string {
+	if strings.Contains(pattern, "*") {
+		panic(fmt.Sprintf("wildcard route %s cannot be described in the spec", pattern))
+	}
+	return chiParamRegexp.ReplaceAllString(pattern, "{$1}")
+}
+
+// errorResponses maps HTTP status codes to general error responses, see addErrors
+var errorResponses = map[int]string{
+	http.StatusBadRequest:            "BadRequest",
+	http.StatusUnauthorized:          "Unauthorized",
+	http.StatusNotFound:              "NotFound",
+	http.StatusConflict:              "Conflict",
+	http.StatusRequestEntityTooLarge: "RequestTooLarge",
+	http.StatusUnprocessableEntity:   "UnprocessableEntity",
+	http.StatusInternalServerError:   "InternalError",
+	http.StatusServiceUnavailable:    "ServiceUnavailable",
+	http.StatusGatewayTimeout:        "GatewayTimeout",
 }
 
 func addErrors(spec *APISpec) {
 	// error payloads
 	spec.addTypeSchema("v1.ErrorResponse", &payloads.ErrorResponse{})
+	spec.addTypeSchema("v1.ProblemResponse", &payloads.ProblemResponse{})
 
 	// general error responses
-	spec.addResponse("NotFound", "The requested resource was not found", "#/components/schemas/v1.ErrorResponse")
-	spec.addResponse("InternalError", "The server encountered an internal error", "#/components/schemas/v1.ErrorResponse")
-	spec.addResponse("BadRequest", "The request's parameters are invalid", "#/components/schemas/v1.ErrorResponse")
+	spec.addErrorResponse("NotFound", "The requested resource was not found")
+	spec.addErrorResponse("InternalError", "The server encountered an internal error")
+	spec.addErrorResponse("BadRequest", "The request's parameters are invalid")
+	spec.addErrorResponse("Unauthorized", "The request is missing a valid x-rh-identity header")
+	spec.addErrorResponse("Conflict", "The request conflicts with existing data or a concurrent request")
+	spec.addErrorResponse("RequestTooLarge", "The request body exceeds the size limit")
+	spec.addErrorResponse("UnprocessableEntity", "The request violates a data constraint")
+	spec.addErrorResponse("ServiceUnavailable", "The database is temporarily unavailable, the request can be retried")
+	spec.addErrorResponse("GatewayTimeout", "The database did not respond in time")
 }
 
 // Enables nullable fields in OpenAPI spec by go tag nullable: "true".
 // Keep in mind, that this generates OpenAPI 3 type nullable,
 // which is backward incompatible with Swagger (OpenAPI 2).
-var enableNullableCustomizer = openapi3gen.SchemaCustomizer(
-	func(_name string, _t reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) error {
-		if tag.Get("nullable") == "true" {
-			schema.Nullable = true
+func enableNullableCustomizer(_name string, _t reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) error {
+	if tag.Get("nullable") == "true" {
+		schema.Nullable = true
+	}
+	return nil
+}
+
+// Emits constraints from the validate go tag (see internal/validation) into the schema,
+// so the spec describes the same constraints the service enforces.
+func validationCustomizer(_name string, t reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) error {
+	if t.Kind() == reflect.Struct {
+		schema.Required = append(schema.Required, requiredFields(t)...)
+	}
+
+	for _, rule := range validation.ParseTag(tag.Get(validation.TagName)) {
+		switch rule.Name {
+		case "min", "max":
+			value, err := strconv.ParseUint(rule.Param, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid %s validation parameter: %w", rule.Name, err)
+			}
+			setLimit(schema, rule.Name, value)
+		case "email":
+			schema.Format = "email"
+		}
+	}
+	return nil
+}
+
+// requiredFields returns JSON names of required fields including fields of embedded structs
+func requiredFields(t reflect.Type) []string {
+	var result []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if validation.IsFlattened(field) {
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			result = append(result, requiredFields(fieldType)...)
+			continue
+		}
+		name, ok := validation.JSONName(field)
+		if !ok {
+			continue
+		}
+		for _, rule := range validation.ParseTag(field.Tag.Get(validation.TagName)) {
+			if rule.Name == "required" {
+				result = append(result, name)
+			}
+		}
+	}
+	return result
+}
+
+func setLimit(schema *openapi3.Schema, rule string, value uint64) {
+	switch {
+	case schema.Type == "string" && rule == "min":
+		schema.MinLength = value
+	case schema.Type == "string" && rule == "max":
+		schema.MaxLength = &value
+	case schema.Type == "array" && rule == "min":
+		schema.MinItems = value
+	case schema.Type == "array" && rule == "max":
+		schema.MaxItems = &value
+	case rule == "min":
+		limit := float64(value)
+		schema.Min = &limit
+	case rule == "max":
+		limit := float64(value)
+		schema.Max = &limit
+	}
+}
+
+var schemaCustomizer = openapi3gen.SchemaCustomizer(
+	func(name string, t reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) error {
+		if err := enableNullableCustomizer(name, t, tag, schema); err != nil {
+			return err
 		}
-		return nil
+		return validationCustomizer(name, t, tag, schema)
 	},
 )
 
 type APISpec struct {
+	Paths      openapi3.Paths      `json:"paths" yaml:"paths"`
 	Components openapi3.Components `json:"components,omitempty" yaml:"components,omitempty"`
 	Servers    openapi3.Servers    `json:"servers,omitempty" yaml:"servers,omitempty"`
 }
@@ -57,6 +210,7 @@ func NewSpec() APISpec {
 			},
 		},
 	}
+	spec.Paths = openapi3.Paths{}
 	spec.Components = openapi3.NewComponents()
 	spec.Components.Schemas = make(map[string]*openapi3.SchemaRef)
 	spec.Components.Responses = make(map[string]*openapi3.ResponseRef)
@@ -64,24 +218,43 @@ func NewSpec() APISpec {
 }
 
 func (spec APISpec) addTypeSchema(name string, model interface{}) {
-	schema, err := openapi3gen.NewSchemaRefForValue(model, spec.Components.Schemas, enableNullableCustomizer)
+	schema, err := openapi3gen.NewSchemaRefForValue(model, spec.Components.Schemas, schemaCustomizer)
 	if err != nil {
 		panic(err)
 	}
 	spec.Components.Schemas[name] = schema
 }
 
-func (spec APISpec) addResponse(name string, description string, ref string) {
-	response := openapi3.NewResponse().WithDescription(description).WithJSONSchemaRef(&openapi3.SchemaRef{Ref: ref})
+// addPayloadSchema registers schema of the payload named by its type, e.g. v1.HelloRequest,
+// and returns reference to it
+func (spec APISpec) addPayloadSchema(model interface{}) *openapi3.SchemaRef {
+	name := "v1." + reflect.Indirect(reflect.ValueOf(model)).Type().Name()
+	if _, ok := spec.Components.Schemas[name]; !ok {
+		spec.addTypeSchema(name, model)
+	}
+	return &openapi3.SchemaRef{Ref: "#/components/schemas/" + name}
+}
+
+// addErrorResponse adds response with the error payload, clients accepting
+// problem details get them instead (see payloads.RenderError)
+func (spec APISpec) addErrorResponse(name string, description string) {
+	response := openapi3.NewResponse().WithDescription(description)
+	response.Content = openapi3.Content{
+		"application/json": openapi3.NewMediaType().
+			WithSchemaRef(&openapi3.SchemaRef{Ref: "#/components/schemas/v1.ErrorResponse"}),
+		payloads.ProblemContentType: openapi3.NewMediaType().
+			WithSchemaRef(&openapi3.SchemaRef{Ref: "#/components/schemas/v1.ProblemResponse"}),
+	}
 	spec.Components.Responses[name] = &openapi3.ResponseRef{Value: response}
 }
 
-func main() {
+// generate returns the loaded spec and its YAML and JSON representations
+func generate() (*openapi3.T, []byte, []byte) {
 	spec := NewSpec()
-	addPayloads(&spec)
 	addErrors(&spec)
+	addPaths(&spec)
 
-	bufferYAML, err := os.ReadFile("./cmd/openapi_spec/paths.yml")
+	bufferYAML, err := os.ReadFile("./cmd/openapi_spec/info.yml")
 	if err != nil {
 		panic(err)
 	}
@@ -102,11 +275,62 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	return loadedSchema, bufferYAML, bufferJson
+}
 
-	if err = os.WriteFile("./api/openapi.gen.yml", bufferYAML, 0o644); err != nil {
+// checkFile compares the generated content with the file and prints the difference
+func checkFile(path string, generated []byte) bool {
+	committed, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
 		panic(err)
 	}
-	if err = os.WriteFile("./api/openapi.gen.json", bufferJson, 0o644); err != nil {
-		panic(err)
+	if bytes.Equal(committed, generated) {
+		return true
+	}
+	fmt.Print(unifiedDiff(path, committed, generated))
+	return false
+}
+
+func main() {
+	check := flag.Bool("check", false, "verify the generated files are up to date instead of writing them")
+	previous := flag.String("previous", "", "report breaking changes against a previous spec (YAML or JSON file)")
+	flag.Parse()
+
+	spec, bufferYAML, bufferJson := generate()
+	ok := true
+
+	if *previous != "" {
+		previousSpec, err := openapi3.NewLoader().LoadFromFile(*previous)
+		if err != nil {
+			panic(err)
+		}
+		changes := breakingChanges(previousSpec, spec)
+		for _, change := range changes {
+			fmt.Printf("BREAKING: %s\n", change)
+		}
+		if len(changes) > 0 {
+			fmt.Printf("%d breaking change(s) against %s\n", len(changes), *previous)
+			ok = false
+		}
+	}
+
+	if *check {
+		yamlOk := checkFile("./api/openapi.gen.yml", bufferYAML)
+		jsonOk := checkFile("./api/openapi.gen.json", bufferJson)
+		if !yamlOk || !jsonOk {
+			fmt.Println("The generated OpenAPI spec is outdated, run 'make generate-openapi'")
+			ok = false
+		}
+	} else {
+		if err := os.WriteFile("./api/openapi.gen.yml", bufferYAML, 0o644); err != nil {
+			panic(err)
+		}
+		if err := os.WriteFile("./api/openapi.gen.json", bufferJson, 0o644); err != nil {
+			panic(err)
+		}
+	}
+
+	if !ok {
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,188 @@
+// Package apispec enforces the embedded OpenAPI spec on the served API.
+package apispec
+
+import (
+	"bytes"
+	"consoledot-go-template/api"
+	"consoledot-go-template/internal/logging"
+	"consoledot-go-template/internal/payloads"
+	"consoledot-go-template/internal/validation"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+func init() {
+	// kin-openapi does not check the email format by default, use the same check as payload validation
+	openapi3.DefineStringFormatCallback("email", func(value string) error {
+		if !validation.IsEmail(value) {
+			return ErrInvalidEmail
+		}
+		return nil
+	})
+}
+
+var ErrInvalidEmail = errors.New("must be a valid email address")
+
+// Validator validates requests and responses of operations described in the spec.
+// Requests for paths or methods not in the spec are passed through untouched.
+type Validator struct {
+	router            routers.Router
+	validateRequests  bool
+	validateResponses bool
+	options           *openapi3filter.Options
+}
+
+// NewValidator creates validator of the embedded spec. Invalid requests are rejected
+// with 400 when validateRequests is set, responses not matching the spec are logged
+// as warnings when validateResponses is set. Response validation buffers every
+// response body, it is meant for development and testing.
+func NewValidator(validateRequests, validateResponses bool) (*Validator, error) {
+	spec, err := api.Spec()
+	if err != nil {
+		return nil, err
+	}
+
+	// paths are matched relative to the API prefix, see routePath
+	spec.Servers = nil
+	router, err := legacy.NewRouter(spec)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create OpenAPI router: %w", err)
+	}
+
+	return &Validator{
+		router:            router,
+		validateRequests:  validateRequests,
+		validateResponses: validateResponses,
+		options: &openapi3filter.Options{
+			MultiError:            true,
+			IncludeResponseStatus: true,
+			SkipSettingDefaults:   true,
+			AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+		},
+	}, nil
+}
+
+// Middleware validates requests and responses. It must be used in the router
+// mounted under the API prefix, so the chi route path is relative to the spec servers.
+func (v *Validator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		route, pathParams, err := v.findRoute(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    v.options,
+		}
+		if v.validateRequests {
+			if err := openapi3filter.ValidateRequest(ctx, input); err != nil {
+				renderError(w, r, payloads.NewInvalidRequestError(ctx, "request does not match the API spec", requestError(err)))
+				return
+			}
+		}
+
+		if !v.validateResponses {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var body bytes.Buffer
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		ww.Tee(&body)
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		err = openapi3filter.ValidateResponse(ctx, &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 status,
+			Header:                 ww.Header(),
+			Body:                   io.NopCloser(&body),
+			Options:                v.options,
+		})
+		if err != nil {
+			logging.Logger(ctx).Warn().Err(err).
+				Str("operation", route.Operation.OperationID).
+				Int("status", status).
+				Msg("Response does not match the API spec")
+		}
+	})
+}
+
+func (v *Validator) findRoute(r *http.Request) (*routers.Route, map[string]string, error) {
+	// only method and URL are used by the router, the original request stays untouched
+	url := *r.URL
+	url.Path = routePath(r)
+	return v.router.FindRoute(&http.Request{Method: r.Method, URL: &url})
+}
+
+// routePath returns the path within the mounted API router, e.g. /hellos/1
+func routePath(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePath != "" {
+		return rctx.RoutePath
+	}
+	return r.URL.Path
+}
+
+// requestError converts schema violations into field errors of the error response,
+// other errors (e.g. wrong content type) are returned as they are.
+func requestError(err error) error {
+	if fields := fieldErrors(err, ""); len(fields) > 0 {
+		return fields
+	}
+	return err
+}
+
+func fieldErrors(err error, field string) validation.Errors {
+	var result validation.Errors
+
+	switch err := err.(type) {
+	case openapi3.MultiError:
+		for _, e := range err {
+			result = append(result, fieldErrors(e, field)...)
+		}
+	case *openapi3filter.RequestError:
+		if err.Parameter != nil {
+			field = err.Parameter.Name
+		}
+		if err.Err != nil {
+			result = fieldErrors(err.Err, field)
+		}
+		if len(result) == 0 && err.Parameter != nil {
+			// e.g. value which cannot be parsed or a missing required parameter
+			result = validation.Errors{{Field: field, Message: err.Error()}}
+		}
+	case *openapi3.SchemaError:
+		if pointer := err.JSONPointer(); len(pointer) > 0 {
+			field = strings.Join(pointer, ".")
+		}
+		if field != "" {
+			result = append(result, validation.FieldError{Field: field, Message: err.Reason})
+		}
+	}
+	return result
+}
+
+func renderError(w http.ResponseWriter, r *http.Request, resp payloads.ErrorResponse) {
+	if err := payloads.RenderError(w, r, resp); err != nil {
+		logging.Logger(r.Context()).Error().Err(err).Msg("Unable to render error response")
+	}
+}
@@ -0,0 +1,140 @@
+package apispec_test
+
+import (
+	"bytes"
+	"consoledot-go-template/internal/apispec"
+	"consoledot-go-template/internal/logging"
+	"consoledot-go-template/internal/payloads"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRouter mounts the validated handler under a prefix like the API router does
+func newRouter(t *testing.T, handler http.HandlerFunc) *chi.Mux {
+	t.Helper()
+	validator, err := apispec.NewValidator(true, true)
+	require.NoError(t, err, "failed to create validator")
+
+	apiR := chi.NewRouter()
+	apiR.Use(render.SetContentType(render.ContentTypeJSON))
+	apiR.Use(validator.Middleware)
+	apiR.Get("/hellos", handler)
+	apiR.Post("/hellos", handler)
+	apiR.Get("/unknown", handler)
+
+	router := chi.NewRouter()
+	router.Mount("/api/template/v1", apiR)
+	return router
+}
+
+func serve(t *testing.T, ctx context.Context, router http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, method, path, strings.NewReader(body))
+	require.NoError(t, err, "failed to create request")
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func errorResponse(t *testing.T, rr *httptest.ResponseRecorder) payloads.ErrorResponse {
+	t.Helper()
+	var resp payloads.ErrorResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp), "failed to decode error response")
+	return resp
+}
+
+func TestValidatorRequests(t *testing.T) {
+	var called bool
+	router := newRouter(t, func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	t.Run("invalid parameter", func(t *testing.T) {
+		called = false
+		rr := serve(t, context.Background(), router, "GET", "/api/template/v1/hellos?limit=0&sort_by=nope", "")
+
+		require.Equal(t, http.StatusBadRequest, rr.Code, "Wrong status code")
+		assert.False(t, called, "handler was called")
+		resp := errorResponse(t, rr)
+		require.Equal(t, 2, len(resp.Fields))
+		assert.Equal(t, "limit", resp.Fields[0].Field)
+		assert.Equal(t, "sort_by", resp.Fields[1].Field)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		called = false
+		rr := serve(t, context.Background(), router, "POST", "/api/template/v1/hellos", `{"sender": "not an email", "message": "hi"}`)
+
+		require.Equal(t, http.StatusBadRequest, rr.Code, "Wrong status code")
+		assert.False(t, called, "handler was called")
+		resp := errorResponse(t, rr)
+		require.Equal(t, 1, len(resp.Fields))
+		assert.Equal(t, "sender", resp.Fields[0].Field)
+	})
+
+	t.Run("body is passed to the handler", func(t *testing.T) {
+		var body bytes.Buffer
+		router := newRouter(t, func(w http.ResponseWriter, r *http.Request) {
+			_, err := body.ReadFrom(r.Body)
+			assert.NoError(t, err)
+		})
+		payload := `{"sender": "joe@example.com", "message": "hi"}`
+		serve(t, context.Background(), router, "POST", "/api/template/v1/hellos", payload)
+
+		assert.Equal(t, payload, body.String())
+	})
+
+	t.Run("paths not in spec are not validated", func(t *testing.T) {
+		called = false
+		rr := serve(t, context.Background(), router, "GET", "/api/template/v1/unknown?limit=0", "")
+
+		assert.Equal(t, http.StatusNoContent, rr.Code, "Wrong status code")
+		assert.True(t, called, "handler was not called")
+	})
+}
+
+func TestValidatorResponses(t *testing.T) {
+	var logs bytes.Buffer
+	logger := zerolog.New(&logs)
+	ctx := logging.WithLogger(context.Background(), &logger)
+
+	t.Run("valid response", func(t *testing.T) {
+		logs.Reset()
+		router := newRouter(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data": [], "meta": {"count": 0}, "links": {}}`))
+		})
+		rr := serve(t, ctx, router, "GET", "/api/template/v1/hellos", "")
+
+		assert.Equal(t, http.StatusOK, rr.Code, "Wrong status code")
+		assert.Empty(t, logs.String())
+	})
+
+	t.Run("response drift is logged", func(t *testing.T) {
+		logs.Reset()
+		router := newRouter(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data": "unexpected"}`))
+		})
+		rr := serve(t, ctx, router, "GET", "/api/template/v1/hellos", "")
+
+		assert.Equal(t, http.StatusOK, rr.Code, "Wrong status code")
+		assert.Equal(t, `{"data": "unexpected"}`, rr.Body.String(), "response was modified")
+		assert.Contains(t, logs.String(), `"level":"warn"`)
+		assert.Contains(t, logs.String(), `"operation":"getGreetingList"`)
+	})
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 	clowder "github.com/redhatinsights/app-common-go/pkg/api/v1"
@@ -10,15 +11,48 @@ import (
 
 var config struct {
 	App struct {
-		Port int `env:"PORT" env-default:"8000" env-description:"HTTP port of the API service"`
+		Port            int           `env:"PORT" env-default:"8000" env-description:"HTTP port of the API service"`
+		DrainDelay      time.Duration `env:"DRAIN_DELAY" env-default:"5s" env-description:"delay between failing readiness and server shutdown on SIGTERM"`
+		DrainTimeout    time.Duration `env:"DRAIN_TIMEOUT" env-default:"15s" env-description:"maximum time to wait for in-flight HTTP requests on shutdown"`
+		ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" env-default:"25s" env-description:"the process is killed when the shutdown takes longer (keep below the termination grace period)"`
+		Development     bool          `env:"DEVELOPMENT" env-default:"false" env-description:"development mode exposes internal error details to clients"`
 	} `env-prefix:"APP_"`
+	Server struct {
+		ReadTimeout       time.Duration `env:"READ_TIMEOUT" env-default:"30s" env-description:"maximum duration of reading the whole request including the body"`
+		ReadHeaderTimeout time.Duration `env:"READ_HEADER_TIMEOUT" env-default:"5s" env-description:"maximum duration of reading the request headers"`
+		WriteTimeout      time.Duration `env:"WRITE_TIMEOUT" env-default:"60s" env-description:"maximum duration from the end of reading the request headers to the end of writing the response"`
+		IdleTimeout       time.Duration `env:"IDLE_TIMEOUT" env-default:"120s" env-description:"maximum time to wait for the next request on a keep-alive connection"`
+		MaxHeaderBytes    int           `env:"MAX_HEADER_BYTES" env-default:"1048576" env-description:"maximum size of the request headers in bytes"`
+		MaxBodyBytes      int64         `env:"MAX_BODY_BYTES" env-default:"1048576" env-description:"maximum size of the request body in bytes, larger requests are rejected with 413 (0 disables the limit)"`
+		TLSCertFile       string        `env:"TLS_CERT_FILE" env-default:"" env-description:"path to the TLS certificate, the API is served over HTTPS when both certificate and key are set"`
+		TLSKeyFile        string        `env:"TLS_KEY_FILE" env-default:"" env-description:"path to the TLS private key"`
+	} `env-prefix:"SERVER_"`
 	Database struct {
-		Host     string `env:"HOST" env-default:"localhost" env-description:"main database hostname"`
-		Port     uint16 `env:"PORT" env-default:"5432" env-description:"main database port"`
-		Name     string `env:"NAME" env-default:"hellos" env-description:"main database name"`
-		User     string `env:"USER" env-default:"postgres" env-description:"main database username"`
-		Password string `env:"PASSWORD" env-default:"" env-description:"main database password"`
+		Host                 string        `env:"HOST" env-default:"localhost" env-description:"main database hostname"`
+		Port                 uint16        `env:"PORT" env-default:"5432" env-description:"main database port"`
+		Name                 string        `env:"NAME" env-default:"hellos" env-description:"main database name"`
+		User                 string        `env:"USER" env-default:"postgres" env-description:"main database username"`
+		Password             string        `env:"PASSWORD" env-default:"" env-description:"main database password"`
+		MigrationLockTimeout time.Duration `env:"MIGRATION_LOCK_TIMEOUT" env-default:"5m" env-description:"maximum time to wait for migrations run by another process (0 waits forever)"`
+		MigrationWait        bool          `env:"MIGRATION_WAIT" env-default:"false" env-description:"API waits at startup until the database is migrated to the embedded version"`
+		MigrationWaitTimeout time.Duration `env:"MIGRATION_WAIT_TIMEOUT" env-default:"5m" env-description:"maximum time the API waits for migrations at startup (0 waits forever)"`
+		SchemaCheck          string        `env:"SCHEMA_CHECK" env-default:"fail" env-description:"API startup check of the schema behind the binary: fail (exit), readiness (fail readiness until migrated) or off"`
 	} `env-prefix:"DATABASE_"`
+	Prometheus struct {
+		Port int    `env:"PORT" env-default:"9000" env-description:"HTTP port of the Prometheus metrics endpoint"`
+		Path string `env:"PATH" env-default:"/metrics" env-description:"path of the Prometheus metrics endpoint"`
+	} `env-prefix:"PROMETHEUS_"`
+	Telemetry struct {
+		Enabled      bool    `env:"ENABLED" env-default:"false" env-description:"OpenTelemetry tracing exporter"`
+		Endpoint     string  `env:"ENDPOINT" env-default:"localhost:4318" env-description:"OTLP HTTP collector endpoint (host:port)"`
+		Insecure     bool    `env:"INSECURE" env-default:"true" env-description:"disable TLS for the OTLP collector connection"`
+		SamplerRatio float64 `env:"SAMPLER_RATIO" env-default:"0.1" env-description:"ratio of sampled traces started by the service (0.0 - 1.0)"`
+		ServiceName  string  `env:"SERVICE_NAME" env-default:"" env-description:"service name reported in traces (default is the binary name)"`
+	} `env-prefix:"TELEMETRY_"`
+	OpenAPI struct {
+		ValidateRequests  bool `env:"VALIDATE_REQUESTS" env-default:"false" env-description:"reject requests not matching the OpenAPI spec with 400"`
+		ValidateResponses bool `env:"VALIDATE_RESPONSES" env-default:"false" env-description:"log responses not matching the OpenAPI spec as warnings (development and testing only)"`
+	} `env-prefix:"OPENAPI_"`
 	Logging struct {
 		Level         string `env:"LEVEL" env-default:"info" env-description:"logger level (trace, debug, info, warn, error, fatal, panic)"`
 		DatabaseLevel string `env:"DB_LEVEL" env-default:"info" env-description:"database logs level (trace, debug, info, warn, error, fatal, panic)"`
@@ -35,7 +69,11 @@ var config struct {
 
 var (
 	Application = &config.App
+	Server      = &config.Server
 	Database    = &config.Database
+	Prometheus  = &config.Prometheus
+	Telemetry   = &config.Telemetry
+	OpenAPI     = &config.OpenAPI
 	Logging     = &config.Logging
 	Cloudwatch  = &config.Cloudwatch
 )
@@ -64,6 +102,16 @@ func Initialize(configFiles ...string) {
 		}
 	}
 
+	switch config.Database.SchemaCheck {
+	case "fail", "readiness", "off":
+	default:
+		panic(fmt.Errorf("invalid DATABASE_SCHEMA_CHECK %q, use fail, readiness or off", config.Database.SchemaCheck))
+	}
+
+	if (config.Server.TLSCertFile == "") != (config.Server.TLSKeyFile == "") {
+		panic(fmt.Errorf("both SERVER_TLS_CERT_FILE and SERVER_TLS_KEY_FILE must be set to enable TLS"))
+	}
+
 	if clowder.IsClowderEnabled() {
 		cfg := clowder.LoadedConfig
 
@@ -74,6 +122,10 @@ func Initialize(configFiles ...string) {
 		config.Database.Password = cfg.Database.Password
 		config.Database.Name = cfg.Database.Name
 
+		// prometheus
+		config.Prometheus.Port = cfg.MetricsPort
+		config.Prometheus.Path = cfg.MetricsPath
+
 		// cloudwatch (is blank in ephemeral)
 		cw := cfg.Logging.Cloudwatch
 		if cw.Region != "" && cw.AccessKeyId != "" && cw.SecretAccessKey != "" && cw.LogGroup != "" {
@@ -14,3 +14,8 @@ func BinaryName() string {
 func InClowder() bool {
 	return clowder.IsClowderEnabled()
 }
+
+// TLSEnabled returns true when the API is served over HTTPS
+func TLSEnabled() bool {
+	return Server.TLSCertFile != "" && Server.TLSKeyFile != ""
+}
@@ -1,8 +1,98 @@
 package dao
 
 import (
+	"context"
+	"errors"
+
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 // ErrNoRows is returned when there are no rows in the result
 var ErrNoRows = pgx.ErrNoRows
+
+// ErrMissingAccount is returned when tenant scoped data are accessed without
+// an account in the context
+var ErrMissingAccount = errors.New("account missing in the context")
+
+var (
+	// ErrConflict is returned when the data conflict with existing data (e.g. a duplicate key)
+	// or with a concurrent transaction, retrying the request may succeed.
+	ErrConflict = errors.New("conflict")
+
+	// ErrConstraintViolation is returned when the data violate a constraint,
+	// e.g. a foreign key or a check, the same request will fail again.
+	ErrConstraintViolation = errors.New("constraint violation")
+
+	// ErrUnavailable is returned when the database cannot serve the query at the moment,
+	// e.g. when it has too many connections.
+	ErrUnavailable = errors.New("database unavailable")
+
+	// ErrTimeout is returned when the query was canceled or has timed out
+	ErrTimeout = errors.New("database timeout")
+)
+
+// PostgreSQL error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgCheckViolation       = "23514"
+	pgNotNullViolation     = "23502"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+	pgTooManyConnections   = "53300"
+	pgCannotConnectNow     = "57P03"
+	pgQueryCanceled        = "57014"
+)
+
+// pgErrorKinds classifies PostgreSQL error codes into DAO errors
+var pgErrorKinds = map[string]error{
+	pgUniqueViolation:      ErrConflict,
+	pgSerializationFailure: ErrConflict,
+	pgDeadlockDetected:     ErrConflict,
+	pgForeignKeyViolation:  ErrConstraintViolation,
+	pgCheckViolation:       ErrConstraintViolation,
+	pgNotNullViolation:     ErrConstraintViolation,
+	pgTooManyConnections:   ErrUnavailable,
+	pgCannotConnectNow:     ErrUnavailable,
+	pgQueryCanceled:        ErrTimeout,
+}
+
+// Error is a database error classified by its kind, it matches the kind
+// (e.g. ErrConflict) by errors.Is and unwraps to the original error.
+type Error struct {
+	Kind error
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Kind.Error() + ": " + e.Err.Error()
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// TranslateError classifies database errors, errors which are not recognized
+// are returned unchanged.
+func TranslateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		if kind, ok := pgErrorKinds[pgErr.Code]; ok {
+			return &Error{Kind: kind, Err: err}
+		}
+		return err
+	}
+	if pgconn.Timeout(err) || errors.Is(err, context.DeadlineExceeded) {
+		return &Error{Kind: ErrTimeout, Err: err}
+	}
+	return err
+}
@@ -0,0 +1,47 @@
+package dao_test
+
+import (
+	"consoledot-go-template/internal/dao"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslateError(t *testing.T) {
+	tests := []struct {
+		code string
+		kind error
+	}{
+		{"23505", dao.ErrConflict},
+		{"40001", dao.ErrConflict},
+		{"23503", dao.ErrConstraintViolation},
+		{"23514", dao.ErrConstraintViolation},
+		{"53300", dao.ErrUnavailable},
+		{"57014", dao.ErrTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			pgErr := &pgconn.PgError{Code: tt.code, Message: "failed"}
+			err := fmt.Errorf("pgx error: %w", dao.TranslateError(pgErr))
+
+			assert.ErrorIs(t, err, tt.kind)
+			var unwrapped *pgconn.PgError
+			assert.True(t, errors.As(err, &unwrapped), "original error is not wrapped")
+		})
+	}
+
+	t.Run("context deadline", func(t *testing.T) {
+		assert.ErrorIs(t, dao.TranslateError(context.DeadlineExceeded), dao.ErrTimeout)
+	})
+
+	t.Run("unknown errors are unchanged", func(t *testing.T) {
+		pgErr := &pgconn.PgError{Code: "42P01"}
+		assert.Equal(t, error(pgErr), dao.TranslateError(pgErr))
+		assert.Equal(t, dao.ErrNoRows, dao.TranslateError(dao.ErrNoRows))
+		assert.Nil(t, dao.TranslateError(nil))
+	})
+}
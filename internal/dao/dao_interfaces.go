@@ -5,10 +5,31 @@ import (
 	"context"
 )
 
+var GetAccountDao func(ctx context.Context) AccountDao
+
+// AccountDao groups access methods for accounts (tenants).
+type AccountDao interface {
+	// GetOrCreateByIdentity returns account for the given organization, creating it when it
+	// does not exist yet. Account number is updated when it was not known before. Account numbers
+	// are unique, a number owned by another organization is not taken over and the account keeps
+	// its previous number.
+	GetOrCreateByIdentity(ctx context.Context, orgID string, accountNumber string) (*models.Account, error)
+}
+
 var GetHelloDao func(ctx context.Context) HelloDao
 
 // HelloDao groups access methods for access to state of hello.
 type HelloDao interface {
-	List(ctx context.Context, limit, offset int64) ([]*models.Hello, error)
+	List(ctx context.Context, params *HelloListParams, limit, offset int64) ([]*models.Hello, error)
+	// ListAfter returns hellos following afterID in the order by ID (keyset pagination),
+	// sorting by other fields is not supported. Zero afterID returns the first page.
+	ListAfter(ctx context.Context, params *HelloListParams, afterID, limit int64) ([]*models.Hello, error)
+	Count(ctx context.Context, params *HelloListParams) (int64, error)
 	Record(ctx context.Context, message *models.Hello) error
+	// GetByID returns ErrNoRows when the hello does not exist.
+	GetByID(ctx context.Context, id int64) (*models.Hello, error)
+	// Update modifies sender and message of the hello, returns ErrNoRows when it does not exist.
+	Update(ctx context.Context, message *models.Hello) error
+	// Delete returns ErrNoRows when the hello does not exist.
+	Delete(ctx context.Context, id int64) error
 }
@@ -0,0 +1,23 @@
+package dao
+
+import "errors"
+
+// ErrInvalidSortField is returned when a list is sorted by a field which is not allowed
+var ErrInvalidSortField = errors.New("invalid sort field")
+
+// HelloSortFields lists fields the hello list can be sorted by, they are named by the columns
+// and also make the sort_by pattern of the spec
+var HelloSortFields = []string{"id", "sender", "recipient", "message"}
+
+// HelloListParams filters and orders hello lists. A nil value lists all hellos ordered by ID.
+type HelloListParams struct {
+	// Sender, Recipient and Message are case-insensitive substring filters, empty value matches all.
+	Sender    string
+	Recipient string
+	Message   string
+
+	// SortBy is one of HelloSortFields, empty value sorts by ID. Items with the same
+	// value are always ordered by ID.
+	SortBy   string
+	SortDesc bool
+}
@@ -0,0 +1,54 @@
+package pgx
+
+import (
+	"consoledot-go-template/internal/dao"
+	"consoledot-go-template/internal/db"
+	"consoledot-go-template/internal/logging"
+	"consoledot-go-template/internal/models"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+func init() {
+	dao.GetAccountDao = getAccountDao
+}
+
+type accountDaoPgx struct{}
+
+func getAccountDao(ctx context.Context) dao.AccountDao {
+	return &accountDaoPgx{}
+}
+
+func (x *accountDaoPgx) GetOrCreateByIdentity(ctx context.Context, orgID string, accountNumber string) (*models.Account, error) {
+	account := &models.Account{}
+	query := `-- name: GetAccountByOrgID
+		SELECT * FROM accounts WHERE org_id = $1`
+	err := pgxscan.Get(ctx, db.Pool, account, query, orgID)
+	if err == nil && (accountNumber == "" || account.AccountNumber.String == accountNumber) {
+		return account, nil
+	}
+	if err != nil && !errors.Is(err, dao.ErrNoRows) {
+		return nil, fmt.Errorf("pgx error: %w", dao.TranslateError(err))
+	}
+
+	// the account does not exist yet or its account number has changed, account numbers are
+	// unique and a number owned by another organization is never taken over
+	query = `-- name: UpsertAccount
+		INSERT INTO accounts (org_id, account_number)
+		VALUES ($1, (SELECT NULLIF($2, '') WHERE NOT EXISTS (
+			SELECT 1 FROM accounts WHERE account_number = $2 AND org_id <> $1)))
+		ON CONFLICT (org_id) DO UPDATE
+		SET account_number = COALESCE(EXCLUDED.account_number, accounts.account_number)
+		RETURNING *`
+	if err = pgxscan.Get(ctx, db.Pool, account, query, orgID, accountNumber); err != nil {
+		return nil, fmt.Errorf("pgx error: %w", dao.TranslateError(err))
+	}
+	if accountNumber != "" && account.AccountNumber.String != accountNumber {
+		logging.Logger(ctx).Warn().Str("org_id", orgID).Str("account_number", accountNumber).
+			Msg("Account number is owned by another organization, keeping its owner")
+	}
+	return account, nil
+}
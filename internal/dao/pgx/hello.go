@@ -3,9 +3,11 @@ package pgx
 import (
 	"consoledot-go-template/internal/dao"
 	"consoledot-go-template/internal/db"
+	"consoledot-go-template/internal/identity"
 	"consoledot-go-template/internal/models"
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/georgysavva/scany/v2/pgxscan"
 )
@@ -20,28 +22,219 @@ func getHelloDao(ctx context.Context) dao.HelloDao {
 	return &helloDaoPgx{}
 }
 
-func (x *helloDaoPgx) List(ctx context.Context, limit, offset int64) ([]*models.Hello, error) {
-	query := `SELECT * FROM hellos ORDER BY id LIMIT $1 OFFSET $2`
-	rows, err := db.Pool.Query(ctx, query, limit, offset)
+// helloSortColumns translates sort fields to SQL, it is the only source of ORDER BY clauses.
+// Sort fields are named by their columns.
+var helloSortColumns = sortColumns(dao.HelloSortFields)
+
+func sortColumns(fields []string) map[string]string {
+	columns := make(map[string]string, len(fields))
+	for _, field := range fields {
+		columns[field] = field
+	}
+	return columns
+}
+
+// helloConditions returns the WHERE clause conditions and arguments for the account
+// and list parameters, placeholders are numbered from 1.
+func helloConditions(accountID int64, params *dao.HelloListParams) ([]string, []interface{}) {
+	conditions := []string{"account_id = $1"}
+	args := []interface{}{accountID}
+	if params == nil {
+		return conditions, args
+	}
+
+	filters := []struct {
+		column string
+		value  string
+	}{
+		{"sender", params.Sender},
+		{"recipient", params.Recipient},
+		{"message", params.Message},
+	}
+	for _, filter := range filters {
+		if filter.value == "" {
+			continue
+		}
+		args = append(args, escapeLike(filter.value))
+		conditions = append(conditions, fmt.Sprintf(`%s ILIKE '%%' || $%d || '%%'`, filter.column, len(args)))
+	}
+	return conditions, args
+}
+
+func helloOrderBy(params *dao.HelloListParams) (string, error) {
+	if params == nil || params.SortBy == "" {
+		return "id", nil
+	}
+
+	column, ok := helloSortColumns[params.SortBy]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", dao.ErrInvalidSortField, params.SortBy)
+	}
+	direction := "ASC"
+	if params.SortDesc {
+		direction = "DESC"
+	}
+	if column == "id" {
+		return fmt.Sprintf("id %s", direction), nil
+	}
+	return fmt.Sprintf("%s %s, id %s", column, direction, direction), nil
+}
+
+// escapeLike escapes LIKE pattern characters, backslash is the default escape character
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+func (x *helloDaoPgx) List(ctx context.Context, params *dao.HelloListParams, limit, offset int64) ([]*models.Hello, error) {
+	accountID := identity.AccountID(ctx)
+	if accountID == 0 {
+		return nil, dao.ErrMissingAccount
+	}
+
+	orderBy, err := helloOrderBy(params)
 	if err != nil {
-		return nil, fmt.Errorf("query hellos error: %w", err)
+		return nil, err
+	}
+	conditions, args := helloConditions(accountID, params)
+	args = append(args, limit, offset)
+	query := fmt.Sprintf(`-- name: ListHellos
+		SELECT * FROM hellos WHERE %s ORDER BY %s LIMIT $%d OFFSET $%d`,
+		strings.Join(conditions, " AND "), orderBy, len(args)-1, len(args))
+
+	rows, err := db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query hellos error: %w", dao.TranslateError(err))
 	}
 
 	var result []*models.Hello
 	if err = pgxscan.ScanAll(&result, rows); err != nil {
-		return nil, fmt.Errorf("scanning hello rows error: %w", err)
+		return nil, fmt.Errorf("scanning hello rows error: %w", dao.TranslateError(err))
+	}
+	return result, nil
+}
+
+func (x *helloDaoPgx) ListAfter(ctx context.Context, params *dao.HelloListParams, afterID, limit int64) ([]*models.Hello, error) {
+	accountID := identity.AccountID(ctx)
+	if accountID == 0 {
+		return nil, dao.ErrMissingAccount
+	}
+	if params != nil && params.SortBy != "" && params.SortBy != "id" {
+		return nil, fmt.Errorf("%w: keyset pagination only supports id", dao.ErrInvalidSortField)
+	}
+
+	orderBy, err := helloOrderBy(params)
+	if err != nil {
+		return nil, err
+	}
+	conditions, args := helloConditions(accountID, params)
+	if afterID > 0 {
+		args = append(args, afterID)
+		if params != nil && params.SortDesc {
+			conditions = append(conditions, fmt.Sprintf("id < $%d", len(args)))
+		} else {
+			conditions = append(conditions, fmt.Sprintf("id > $%d", len(args)))
+		}
+	}
+	args = append(args, limit)
+	query := fmt.Sprintf(`-- name: ListHellosAfter
+		SELECT * FROM hellos WHERE %s ORDER BY %s LIMIT $%d`,
+		strings.Join(conditions, " AND "), orderBy, len(args))
+
+	rows, err := db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query hellos error: %w", dao.TranslateError(err))
+	}
+
+	var result []*models.Hello
+	if err = pgxscan.ScanAll(&result, rows); err != nil {
+		return nil, fmt.Errorf("scanning hello rows error: %w", dao.TranslateError(err))
+	}
+	return result, nil
+}
+
+func (x *helloDaoPgx) Count(ctx context.Context, params *dao.HelloListParams) (int64, error) {
+	accountID := identity.AccountID(ctx)
+	if accountID == 0 {
+		return 0, dao.ErrMissingAccount
+	}
+
+	conditions, args := helloConditions(accountID, params)
+	query := fmt.Sprintf(`-- name: CountHellos
+		SELECT COUNT(*) FROM hellos WHERE %s`, strings.Join(conditions, " AND "))
+	var result int64
+	if err := db.Pool.QueryRow(ctx, query, args...).Scan(&result); err != nil {
+		return 0, fmt.Errorf("pgx error: %w", dao.TranslateError(err))
 	}
 	return result, nil
 }
 
 func (x *helloDaoPgx) Record(ctx context.Context, hello *models.Hello) error {
-	query := `
-		INSERT INTO hellos (from, to, message)
-		VALUES ($1, $2, $3) RETURNING id`
+	accountID := identity.AccountID(ctx)
+	if accountID == 0 {
+		return dao.ErrMissingAccount
+	}
+
+	query := `-- name: RecordHello
+		INSERT INTO hellos (account_id, sender, recipient, message)
+		VALUES ($1, $2, $3, $4) RETURNING id`
 
-	err := db.Pool.QueryRow(ctx, query, hello.From, hello.To, hello.Message).Scan(&hello.ID)
+	err := db.Pool.QueryRow(ctx, query, accountID, hello.From, hello.To, hello.Message).Scan(&hello.ID)
 	if err != nil {
-		return fmt.Errorf("pgx error: %w", err)
+		return fmt.Errorf("pgx error: %w", dao.TranslateError(err))
+	}
+	hello.AccountID = accountID
+	return nil
+}
+
+func (x *helloDaoPgx) GetByID(ctx context.Context, id int64) (*models.Hello, error) {
+	accountID := identity.AccountID(ctx)
+	if accountID == 0 {
+		return nil, dao.ErrMissingAccount
+	}
+
+	query := `-- name: GetHelloByID
+		SELECT * FROM hellos WHERE account_id = $1 AND id = $2`
+	result := &models.Hello{}
+	if err := pgxscan.Get(ctx, db.Pool, result, query, accountID, id); err != nil {
+		return nil, fmt.Errorf("pgx error: %w", dao.TranslateError(err))
+	}
+	return result, nil
+}
+
+func (x *helloDaoPgx) Update(ctx context.Context, hello *models.Hello) error {
+	accountID := identity.AccountID(ctx)
+	if accountID == 0 {
+		return dao.ErrMissingAccount
+	}
+
+	query := `-- name: UpdateHello
+		UPDATE hellos SET sender = $3, message = $4
+		WHERE account_id = $1 AND id = $2
+		RETURNING recipient`
+
+	err := db.Pool.QueryRow(ctx, query, accountID, hello.ID, hello.From, hello.Message).Scan(&hello.To)
+	if err != nil {
+		return fmt.Errorf("pgx error: %w", dao.TranslateError(err))
+	}
+	hello.AccountID = accountID
+	return nil
+}
+
+func (x *helloDaoPgx) Delete(ctx context.Context, id int64) error {
+	accountID := identity.AccountID(ctx)
+	if accountID == 0 {
+		return dao.ErrMissingAccount
+	}
+
+	query := `-- name: DeleteHello
+		DELETE FROM hellos WHERE account_id = $1 AND id = $2`
+
+	tag, err := db.Pool.Exec(ctx, query, accountID, id)
+	if err != nil {
+		return fmt.Errorf("pgx error: %w", dao.TranslateError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("delete hello %d: %w", id, dao.ErrNoRows)
 	}
 	return nil
 }
@@ -0,0 +1,39 @@
+package stub
+
+import (
+	"consoledot-go-template/internal/dao"
+	"consoledot-go-template/internal/models"
+	"context"
+	"database/sql"
+)
+
+func init() {
+	dao.GetAccountDao = getAccountDao
+}
+
+type accountDaoStub struct {
+	store []*models.Account
+}
+
+func getAccountDao(ctx context.Context) dao.AccountDao {
+	return getAccountDaoStub(ctx)
+}
+
+func (x *accountDaoStub) GetOrCreateByIdentity(ctx context.Context, orgID string, accountNumber string) (*models.Account, error) {
+	for _, account := range x.store {
+		if account.OrgID == orgID {
+			if accountNumber != "" {
+				account.AccountNumber = sql.NullString{String: accountNumber, Valid: true}
+			}
+			return account, nil
+		}
+	}
+
+	account := &models.Account{
+		ID:            int64(len(x.store) + 1),
+		OrgID:         orgID,
+		AccountNumber: sql.NullString{String: accountNumber, Valid: accountNumber != ""},
+	}
+	x.store = append(x.store, account)
+	return account, nil
+}
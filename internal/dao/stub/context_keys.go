@@ -6,8 +6,28 @@ type daoStubCtxKeyType int
 
 const (
 	helloCtxKey daoStubCtxKeyType = iota
+	accountCtxKey
 )
 
+// WithAccountDao adds account DAO stub to the context
+func WithAccountDao(parent context.Context) context.Context {
+	if parent.Value(accountCtxKey) != nil {
+		panic("dao already in the context")
+	}
+
+	ctx := context.WithValue(parent, accountCtxKey, &accountDaoStub{})
+	return ctx
+}
+
+func getAccountDaoStub(ctx context.Context) *accountDaoStub {
+	var ok bool
+	var resDao *accountDaoStub
+	if resDao, ok = ctx.Value(accountCtxKey).(*accountDaoStub); !ok {
+		panic("dao not in context")
+	}
+	return resDao
+}
+
 // WithHelloDao adds hello DAO stub to the context
 func WithHelloDao(parent context.Context) context.Context {
 	if parent.Value(helloCtxKey) != nil {
@@ -2,8 +2,12 @@ package stub
 
 import (
 	"consoledot-go-template/internal/dao"
+	"consoledot-go-template/internal/identity"
 	"consoledot-go-template/internal/models"
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 )
 
 func init() {
@@ -11,19 +15,165 @@ func init() {
 }
 
 type helloDaoStub struct {
-	store []*models.Hello
+	store  []*models.Hello
+	lastID int64
 }
 
 func getHelloDao(ctx context.Context) dao.HelloDao {
 	return getHelloDaoStub(ctx)
 }
 
-func (x *helloDaoStub) List(ctx context.Context, limit, offset int64) ([]*models.Hello, error) {
-	return x.store, nil
+func (x *helloDaoStub) List(ctx context.Context, params *dao.HelloListParams, limit, offset int64) ([]*models.Hello, error) {
+	hellos, err := x.listAccount(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return page(hellos, limit, offset), nil
+}
+
+func (x *helloDaoStub) ListAfter(ctx context.Context, params *dao.HelloListParams, afterID, limit int64) ([]*models.Hello, error) {
+	if params != nil && params.SortBy != "" && params.SortBy != "id" {
+		return nil, fmt.Errorf("%w: keyset pagination only supports id", dao.ErrInvalidSortField)
+	}
+	hellos, err := x.listAccount(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if afterID == 0 {
+		return page(hellos, limit, 0), nil
+	}
+
+	desc := params != nil && params.SortDesc
+	for i, hello := range hellos {
+		if (!desc && hello.ID > afterID) || (desc && hello.ID < afterID) {
+			return page(hellos[i:], limit, 0), nil
+		}
+	}
+	return []*models.Hello{}, nil
+}
+
+func (x *helloDaoStub) Count(ctx context.Context, params *dao.HelloListParams) (int64, error) {
+	hellos, err := x.listAccount(ctx, params)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(hellos)), nil
+}
+
+// listAccount returns filtered and sorted hellos of the account in the context
+func (x *helloDaoStub) listAccount(ctx context.Context, params *dao.HelloListParams) ([]*models.Hello, error) {
+	accountID := identity.AccountID(ctx)
+	if accountID == 0 {
+		return nil, dao.ErrMissingAccount
+	}
+	if params == nil {
+		params = &dao.HelloListParams{}
+	}
+
+	result := make([]*models.Hello, 0, len(x.store))
+	for _, hello := range x.store {
+		if hello.AccountID == accountID &&
+			containsFold(hello.From, params.Sender) &&
+			containsFold(hello.To, params.Recipient) &&
+			containsFold(hello.Message, params.Message) {
+			result = append(result, hello)
+		}
+	}
+
+	var field func(h *models.Hello) string
+	switch params.SortBy {
+	case "", "id":
+	case "sender":
+		field = func(h *models.Hello) string { return h.From }
+	case "recipient":
+		field = func(h *models.Hello) string { return h.To }
+	case "message":
+		field = func(h *models.Hello) string { return h.Message }
+	default:
+		return nil, fmt.Errorf("%w: %s", dao.ErrInvalidSortField, params.SortBy)
+	}
+
+	// store is ordered by ID, stable sort keeps the ID order for the same values
+	sort.SliceStable(result, func(i, j int) bool {
+		if field != nil {
+			return field(result[i]) < field(result[j])
+		}
+		return result[i].ID < result[j].ID
+	})
+	if params.SortDesc {
+		for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+			result[i], result[j] = result[j], result[i]
+		}
+	}
+	return result, nil
+}
+
+func containsFold(value, substring string) bool {
+	return strings.Contains(strings.ToLower(value), strings.ToLower(substring))
+}
+
+func page(hellos []*models.Hello, limit, offset int64) []*models.Hello {
+	if offset >= int64(len(hellos)) {
+		return []*models.Hello{}
+	}
+	hellos = hellos[offset:]
+	if limit < int64(len(hellos)) {
+		hellos = hellos[:limit]
+	}
+	return hellos
 }
 
 func (x *helloDaoStub) Record(ctx context.Context, hello *models.Hello) error {
-	hello.ID = int64(len(x.store))
+	accountID := identity.AccountID(ctx)
+	if accountID == 0 {
+		return dao.ErrMissingAccount
+	}
+
+	x.lastID++
+	hello.ID = x.lastID
+	hello.AccountID = accountID
 	x.store = append(x.store, hello)
 	return nil
 }
+
+func (x *helloDaoStub) GetByID(ctx context.Context, id int64) (*models.Hello, error) {
+	accountID := identity.AccountID(ctx)
+	if accountID == 0 {
+		return nil, dao.ErrMissingAccount
+	}
+
+	for _, hello := range x.store {
+		if hello.AccountID == accountID && hello.ID == id {
+			return hello, nil
+		}
+	}
+	return nil, dao.ErrNoRows
+}
+
+func (x *helloDaoStub) Update(ctx context.Context, hello *models.Hello) error {
+	existing, err := x.GetByID(ctx, hello.ID)
+	if err != nil {
+		return err
+	}
+
+	existing.From = hello.From
+	existing.Message = hello.Message
+	hello.To = existing.To
+	hello.AccountID = existing.AccountID
+	return nil
+}
+
+func (x *helloDaoStub) Delete(ctx context.Context, id int64) error {
+	accountID := identity.AccountID(ctx)
+	if accountID == 0 {
+		return dao.ErrMissingAccount
+	}
+
+	for i, hello := range x.store {
+		if hello.AccountID == accountID && hello.ID == id {
+			x.store = append(x.store[:i], x.store[i+1:]...)
+			return nil
+		}
+	}
+	return dao.ErrNoRows
+}
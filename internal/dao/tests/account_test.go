@@ -0,0 +1,53 @@
+//go:build database
+// +build database
+
+package tests
+
+import (
+	"consoledot-go-template/internal/dao"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountGetOrCreateByIdentity(t *testing.T) {
+	ctx := context.Background()
+	accountDao := dao.GetAccountDao(ctx)
+	defer reset()
+
+	t.Run("returns existing account", func(t *testing.T) {
+		account, err := accountDao.GetOrCreateByIdentity(ctx, "1", "1")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), account.ID)
+	})
+
+	t.Run("creates account without account number", func(t *testing.T) {
+		account, err := accountDao.GetOrCreateByIdentity(ctx, "10", "")
+		require.NoError(t, err)
+		assert.Equal(t, "10", account.OrgID)
+		assert.False(t, account.AccountNumber.Valid)
+	})
+
+	t.Run("keeps owner of account number used by another organization", func(t *testing.T) {
+		account, err := accountDao.GetOrCreateByIdentity(ctx, "11", "1")
+		require.NoError(t, err)
+		assert.Equal(t, "11", account.OrgID)
+		assert.False(t, account.AccountNumber.Valid)
+
+		owner, err := accountDao.GetOrCreateByIdentity(ctx, "1", "")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), owner.ID)
+		assert.Equal(t, "1", owner.AccountNumber.String)
+	})
+
+	t.Run("keeps previous account number on clash", func(t *testing.T) {
+		_, err := accountDao.GetOrCreateByIdentity(ctx, "12", "12")
+		require.NoError(t, err)
+
+		account, err := accountDao.GetOrCreateByIdentity(ctx, "12", "1")
+		require.NoError(t, err)
+		assert.Equal(t, "12", account.AccountNumber.String)
+	})
+}
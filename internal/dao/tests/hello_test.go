@@ -5,6 +5,7 @@ package tests
 
 import (
 	"consoledot-go-template/internal/dao"
+	"consoledot-go-template/internal/identity"
 	"consoledot-go-template/internal/models"
 	"context"
 	"testing"
@@ -14,7 +15,8 @@ import (
 )
 
 func setupHelloDao(t *testing.T) (dao.HelloDao, context.Context) {
-	ctx := context.Background()
+	// account with ID 1 is created by the integration seed
+	ctx := identity.WithAccount(context.Background(), &models.Account{ID: 1, OrgID: "1"})
 	return dao.GetHelloDao(ctx), ctx
 }
 
@@ -35,6 +37,50 @@ func TestHelloRecord(t *testing.T) {
 		err := helloDao.Record(ctx, hello)
 		require.NoError(t, err)
 
-		assert.Greater(t, 0, hello.ID)
+		assert.Greater(t, hello.ID, int64(0))
+	})
+}
+
+func TestHelloList(t *testing.T) {
+	helloDao, ctx := setupHelloDao(t)
+	defer reset()
+
+	t.Run("lists only hellos of the account", func(t *testing.T) {
+		err := helloDao.Record(ctx, newHello())
+		require.NoError(t, err)
+
+		other, err := dao.GetAccountDao(ctx).GetOrCreateByIdentity(ctx, "2", "2")
+		require.NoError(t, err)
+		otherCtx := identity.WithAccount(context.Background(), other)
+		err = helloDao.Record(otherCtx, newHello())
+		require.NoError(t, err)
+
+		hellos, err := helloDao.List(ctx, nil, 100, 0)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(hellos))
+		assert.Equal(t, int64(1), hellos[0].AccountID)
+	})
+}
+
+func TestHelloListFilter(t *testing.T) {
+	helloDao, ctx := setupHelloDao(t)
+	defer reset()
+
+	t.Run("filters by escaped substring and sorts", func(t *testing.T) {
+		for _, sender := range []string{"b_100%@example.com", "a_100%@example.com", "c100@example.com"} {
+			hello := newHello()
+			hello.From = sender
+			require.NoError(t, helloDao.Record(ctx, hello))
+		}
+
+		params := &dao.HelloListParams{Sender: "_100%", SortBy: "sender"}
+		hellos, err := helloDao.List(ctx, params, 100, 0)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(hellos))
+		assert.Equal(t, "a_100%@example.com", hellos[0].From)
+
+		count, err := helloDao.Count(ctx, params)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
 	})
 }
@@ -2,9 +2,12 @@ package db
 
 import (
 	"consoledot-go-template/internal/config"
+	"consoledot-go-template/internal/identity"
+	"consoledot-go-template/internal/logging"
 	"context"
 	"fmt"
 	"net/url"
+	"os"
 
 	pgxlog "github.com/jackc/pgx-zerolog"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -30,29 +33,37 @@ func Initialize(ctx context.Context, schema string) error {
 		return fmt.Errorf("unable to parse db configuration: %w", err)
 	}
 
+	// identifies the process in pg_stat_activity, e.g. the holder of the migration lock
+	if _, ok := poolConfig.ConnConfig.RuntimeParams["application_name"]; !ok {
+		poolConfig.ConnConfig.RuntimeParams["application_name"] = applicationName()
+	}
+
 	logLevel, configErr := tracelog.LogLevelFromString(config.Logging.DatabaseLevel)
 	if configErr != nil {
 		return fmt.Errorf("cannot parse db log level configuration: %w", configErr)
 	}
 
+	tracer := multiTracer{queryMetricsTracer{}, queryTracingTracer{}}
 	if logLevel > 0 {
 		zeroLogger := pgxlog.NewLogger(log.Logger,
 			pgxlog.WithContextFunc(func(ctx context.Context, logWith zerolog.Context) zerolog.Context {
-				//traceId := ctxval.TraceId(ctx)
-				//if traceId != "" {
-				//	logWith = logWith.Str("trace_id", traceId)
-				//}
-				//accountId := ctxval.AccountIdOrNil(ctx)
-				//if accountId != 0 {
-				//	logWith = logWith.Int64("account_id", accountId)
-				//}
+				if requestID := logging.RequestID(ctx); requestID != "" {
+					logWith = logWith.Str("request_id", requestID)
+				}
+				if traceID := logging.TraceID(ctx); traceID != "" {
+					logWith = logWith.Str("trace_id", traceID)
+				}
+				if accountID := identity.AccountID(ctx); accountID != 0 {
+					logWith = logWith.Int64("account_id", accountID)
+				}
 				return logWith
 			}))
-		poolConfig.ConnConfig.Tracer = &tracelog.TraceLog{
+		tracer = append(tracer, &tracelog.TraceLog{
 			Logger:   zeroLogger,
 			LogLevel: logLevel,
-		}
+		})
 	}
+	poolConfig.ConnConfig.Tracer = tracer
 
 	Pool, err = pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
@@ -66,6 +77,14 @@ func Initialize(ctx context.Context, schema string) error {
 	return nil
 }
 
+func applicationName() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return config.BinaryName()
+	}
+	return fmt.Sprintf("%s@%s", config.BinaryName(), hostname)
+}
+
 func Close() {
 	log.Logger.Info().Msg("Closing all database connections")
 	Pool.Close()
@@ -0,0 +1,43 @@
+//go:build database
+// +build database
+
+// To override application configuration for integration tests, create config/test.env file.
+
+package db
+
+import (
+	"consoledot-go-template/internal/config"
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testSchema is shared with the DAO integration tests, packages must not run in parallel
+const testSchema = "integration"
+
+// resetSchema drops all tables of the test schema including the migration version
+func resetSchema(t *testing.T) {
+	t.Helper()
+	for _, query := range []string{
+		"DROP SCHEMA IF EXISTS " + testSchema + " CASCADE",
+		"CREATE SCHEMA " + testSchema,
+	} {
+		_, err := Pool.Exec(context.Background(), query)
+		require.NoError(t, err)
+	}
+}
+
+func TestMain(m *testing.M) {
+	config.Initialize("config/test.env", "../../config/test.env")
+	err := Initialize(context.Background(), testSchema)
+	if err != nil {
+		panic(fmt.Errorf("cannot connect to database: %w (%s schema)", err, testSchema))
+	}
+
+	exitVal := m.Run()
+	Close()
+	os.Exit(exitVal)
+}
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"consoledot-go-template/internal/config"
+	"consoledot-go-template/internal/db/migrations"
+	"consoledot-go-template/internal/health"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// RegisterHealthChecks registers readiness checks of the database: the connection
+// and the applied migration version.
+func RegisterHealthChecks() {
+	health.Register("database", func(ctx context.Context) (string, error) {
+		if err := Pool.Ping(ctx); err != nil {
+			return "", fmt.Errorf("unable to ping the database: %w", err)
+		}
+		return "", nil
+	})
+	health.Register("migrations", func(ctx context.Context) (string, error) {
+		version, err := CheckSchemaVersion(ctx)
+		switch {
+		case errors.Is(err, ErrSchemaAhead):
+			// the previous version of the service keeps serving during rolling updates
+			return err.Error(), nil
+		case errors.Is(err, ErrSchemaBehind) && config.Database.SchemaCheck == SchemaCheckOff:
+			return err.Error(), nil
+		case err != nil:
+			return "", err
+		}
+		return fmt.Sprintf("schema version %d", version), nil
+	})
+}
+
+// Modes of the schema version check at startup
+const (
+	SchemaCheckFail      = "fail"
+	SchemaCheckReadiness = "readiness"
+	SchemaCheckOff       = "off"
+)
+
+var (
+	ErrSchemaBehind = errors.New("database schema is behind the binary")
+	ErrSchemaAhead  = errors.New("database schema is ahead of the binary")
+)
+
+// CheckSchemaVersion compares the schema version with the version of the last embedded migration.
+// It returns ErrSchemaBehind when migrations are missing and ErrSchemaAhead when the database
+// was migrated by a newer binary.
+func CheckSchemaVersion(ctx context.Context) (int32, error) {
+	version, err := SchemaVersion(ctx)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case version < migrations.LatestVersion:
+		return version, fmt.Errorf("%w: schema version %d, the binary requires %d, run the migrations",
+			ErrSchemaBehind, version, migrations.LatestVersion)
+	case version > migrations.LatestVersion:
+		return version, fmt.Errorf("%w: schema version %d, the binary was built for %d",
+			ErrSchemaAhead, version, migrations.LatestVersion)
+	}
+	return version, nil
+}
+
+// SchemaVersion returns the version of the last applied migration in the schema
+// the pool was initialized with.
+func SchemaVersion(ctx context.Context) (int32, error) {
+	var version int32
+	err := Pool.QueryRow(ctx, "SELECT version FROM schema_version").Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("unable to read schema version: %w", err)
+	}
+	return version, nil
+}
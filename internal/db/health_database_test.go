@@ -0,0 +1,45 @@
+//go:build database
+// +build database
+
+package db
+
+import (
+	"consoledot-go-template/internal/db/migrations"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSchemaVersion(t *testing.T) {
+	ctx := context.Background()
+	resetSchema(t)
+	defer resetSchema(t)
+
+	t.Run("behind", func(t *testing.T) {
+		require.NoError(t, MigrateUp(ctx, testSchema, migrations.LatestVersion-1))
+
+		version, err := CheckSchemaVersion(ctx)
+		assert.ErrorIs(t, err, ErrSchemaBehind)
+		assert.Equal(t, migrations.LatestVersion-1, version)
+	})
+
+	t.Run("current", func(t *testing.T) {
+		require.NoError(t, MigrateUp(ctx, testSchema, -1))
+
+		version, err := CheckSchemaVersion(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, migrations.LatestVersion, version)
+	})
+
+	t.Run("ahead", func(t *testing.T) {
+		// a newer binary has applied a migration unknown to this one
+		_, err := Pool.Exec(ctx, "UPDATE schema_version SET version = $1", migrations.LatestVersion+1)
+		require.NoError(t, err)
+
+		version, err := CheckSchemaVersion(ctx)
+		assert.ErrorIs(t, err, ErrSchemaAhead)
+		assert.Equal(t, migrations.LatestVersion+1, version)
+	})
+}
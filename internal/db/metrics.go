@@ -0,0 +1,118 @@
+package db
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	queryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Duration of database queries by query name",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"query"})
+
+	queryErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_query_errors_total",
+		Help: "Number of failed database queries by query name",
+	}, []string{"query"})
+)
+
+func init() {
+	prometheus.MustRegister(poolCollector{})
+}
+
+// poolCollector exports statistics of the main connection pool. Statistics are read on
+// every scrape, nothing is reported until the pool is initialized.
+type poolCollector struct{}
+
+var (
+	poolAcquiredDesc     = prometheus.NewDesc("db_pool_acquired_connections", "Number of currently acquired connections in the pool", nil, nil)
+	poolIdleDesc         = prometheus.NewDesc("db_pool_idle_connections", "Number of currently idle connections in the pool", nil, nil)
+	poolTotalDesc        = prometheus.NewDesc("db_pool_total_connections", "Total number of connections currently in the pool", nil, nil)
+	poolMaxDesc          = prometheus.NewDesc("db_pool_max_connections", "Maximum size of the pool", nil, nil)
+	poolAcquireDesc      = prometheus.NewDesc("db_pool_acquires_total", "Number of successful acquires from the pool", nil, nil)
+	poolAcquireWaitDesc  = prometheus.NewDesc("db_pool_acquire_duration_seconds_total", "Total time spent waiting for a connection from the pool", nil, nil)
+	poolEmptyAcquireDesc = prometheus.NewDesc("db_pool_empty_acquires_total", "Number of acquires which waited because the pool was empty", nil, nil)
+	poolCanceledDesc     = prometheus.NewDesc("db_pool_canceled_acquires_total", "Number of acquires canceled by a context", nil, nil)
+)
+
+func (poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolAcquiredDesc
+	ch <- poolIdleDesc
+	ch <- poolTotalDesc
+	ch <- poolMaxDesc
+	ch <- poolAcquireDesc
+	ch <- poolAcquireWaitDesc
+	ch <- poolEmptyAcquireDesc
+	ch <- poolCanceledDesc
+}
+
+func (poolCollector) Collect(ch chan<- prometheus.Metric) {
+	if Pool == nil {
+		return
+	}
+
+	stat := Pool.Stat()
+	ch <- prometheus.MustNewConstMetric(poolAcquiredDesc, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(poolIdleDesc, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(poolTotalDesc, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(poolMaxDesc, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(poolAcquireDesc, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolAcquireWaitDesc, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(poolEmptyAcquireDesc, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolCanceledDesc, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+}
+
+type queryMetricsCtxKeyType int
+
+const queryMetricsCtxKey queryMetricsCtxKeyType = iota
+
+type queryStart struct {
+	name string
+	time time.Time
+}
+
+// queryMetricsTracer records duration and errors of every query.
+type queryMetricsTracer struct{}
+
+func (queryMetricsTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryMetricsCtxKey, queryStart{name: QueryName(data.SQL), time: time.Now()})
+}
+
+func (queryMetricsTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryMetricsCtxKey).(queryStart)
+	if !ok {
+		return
+	}
+
+	queryDuration.WithLabelValues(start.name).Observe(time.Since(start.time).Seconds())
+	if data.Err != nil {
+		queryErrorsTotal.WithLabelValues(start.name).Inc()
+	}
+}
+
+var queryNameRegexp = regexp.MustCompile(`^\s*--\s*name:\s*(\w+)`)
+
+// QueryName returns name of the query used in metrics. Queries are named by a leading
+// comment in the form "-- name: ListHellos", unnamed queries are reported by their
+// SQL command (e.g. "select") to keep the cardinality low.
+func QueryName(sql string) string {
+	if match := queryNameRegexp.FindStringSubmatch(sql); match != nil {
+		return match[1]
+	}
+
+	for _, line := range strings.Split(sql, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && !strings.HasPrefix(fields[0], "--") {
+			return strings.ToLower(fields[0])
+		}
+	}
+	return "unnamed"
+}
@@ -0,0 +1,26 @@
+package db_test
+
+import (
+	"consoledot-go-template/internal/db"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryName(t *testing.T) {
+	t.Run("uses name from the leading comment", func(t *testing.T) {
+		assert.Equal(t, "ListHellos", db.QueryName("-- name: ListHellos\n\t\tSELECT * FROM hellos"))
+	})
+
+	t.Run("falls back to the SQL command", func(t *testing.T) {
+		assert.Equal(t, "insert", db.QueryName("\n\t\tINSERT INTO hellos (message) VALUES ($1)"))
+	})
+
+	t.Run("skips comments which do not name the query", func(t *testing.T) {
+		assert.Equal(t, "select", db.QueryName("-- just a comment\nSELECT 1"))
+	})
+
+	t.Run("handles empty query", func(t *testing.T) {
+		assert.Equal(t, "unnamed", db.QueryName(""))
+	})
+}
@@ -0,0 +1,283 @@
+package db
+
+import (
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LintSeverity tells whether the issue fails the lint
+type LintSeverity string
+
+const (
+	LintError   LintSeverity = "error"
+	LintWarning LintSeverity = "warning"
+)
+
+// LintIssue is a problem found in a migration file
+type LintIssue struct {
+	File     string
+	Line     int
+	Severity LintSeverity
+	Message  string
+}
+
+func (i LintIssue) String() string {
+	if i.Line == 0 {
+		return fmt.Sprintf("%s: %s: %s", i.File, i.Severity, i.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s: %s", i.File, i.Line, i.Severity, i.Message)
+}
+
+// LintAllowMarker suppresses a warning of the statement below the comment, it is followed
+// by the rule and a reason, e.g. "-- lint: allow destructive (column was never used)".
+// Markers without a known rule or a reason are errors.
+const LintAllowMarker = "-- lint: allow "
+
+const (
+	lintRuleDestructive  = "destructive"
+	lintRuleVersionTable = "version-table"
+)
+
+// tern splits the files into the migration and its revert by the separator
+const ternSeparator = "---- create above / drop below ----"
+
+var (
+	migrationNamePattern = regexp.MustCompile(`^(\d+)_[a-z0-9_]+\.sql$`)
+
+	// statements which cannot run in a transaction, tern runs them one by one with disable-tx
+	nonTransactionalPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)^\s*(CREATE\s+(UNIQUE\s+)?|DROP\s+|RE)INDEX\s+CONCURRENTLY\b`),
+		regexp.MustCompile(`(?i)^\s*(VACUUM|ALTER\s+SYSTEM|CREATE\s+DATABASE|DROP\s+DATABASE)\b`),
+	}
+
+	// statements losing data, they are only flagged in the migration, not in its revert
+	destructivePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)^\s*DROP\s+(TABLE|SCHEMA|TYPE|VIEW|MATERIALIZED\s+VIEW)\b`),
+		regexp.MustCompile(`(?i)\bDROP\s+(COLUMN|CONSTRAINT)\b`),
+		regexp.MustCompile(`(?i)^\s*(TRUNCATE|DELETE\s+FROM)\b`),
+		regexp.MustCompile(`(?i)\bALTER\s+COLUMN\s+\S+\s+(SET\s+DATA\s+)?TYPE\b`),
+	}
+
+	versionTablePattern = regexp.MustCompile(`(?i)\bschema_version\b`)
+
+	// rule and non-empty reason following LintAllowMarker
+	allowMarkerPattern = regexp.MustCompile(`^([a-z-]+)\s+\(\s*\S.*\)$`)
+)
+
+// LintMigrations checks SQL migrations in the directory: numbering is unique and contiguous,
+// statements which cannot run in a transaction are alone in a disable-tx migration and
+// destructive statements and references to the tern version table are marked by LintAllowMarker.
+func LintMigrations(fsys fs.FS) ([]LintIssue, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("unable to list migrations: %w", err)
+	}
+	sort.Strings(files)
+
+	var issues []LintIssue
+	versions := make(map[int][]string)
+	for _, file := range files {
+		match := migrationNamePattern.FindStringSubmatch(file)
+		if match == nil {
+			issues = append(issues, LintIssue{File: file, Severity: LintError,
+				Message: "name does not match NNN_lowercase_name.sql"})
+			continue
+		}
+		version, _ := strconv.Atoi(match[1])
+		versions[version] = append(versions[version], file)
+
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read migration %s: %w", file, err)
+		}
+		issues = append(issues, lintMigration(file, string(content))...)
+	}
+
+	for version := 1; len(versions) > 0; version++ {
+		names, ok := versions[version]
+		switch {
+		case !ok:
+			issues = append(issues, LintIssue{File: fmt.Sprintf("%03d_*.sql", version), Severity: LintError,
+				Message: "migration number is missing, numbering must be contiguous from 1"})
+		case len(names) > 1:
+			issues = append(issues, LintIssue{File: names[0], Severity: LintError,
+				Message: fmt.Sprintf("migration number %d is used by %s", version, strings.Join(names, ", "))})
+		}
+		delete(versions, version)
+	}
+	return issues, nil
+}
+
+func lintMigration(file, content string) []LintIssue {
+	var issues []LintIssue
+	up, down, _ := strings.Cut(content, ternSeparator)
+	downLine := strings.Count(up, "\n") + 1
+
+	for _, section := range []struct {
+		sql    string
+		line   int
+		revert bool
+	}{{up, 1, false}, {down, downLine, true}} {
+		statements := splitStatements(section.sql)
+		disableTx := disableTxPattern.MatchString(section.sql)
+
+		for _, stmt := range statements {
+			line := section.line + stmt.line - 1
+			issue := func(severity LintSeverity, format string, args ...interface{}) {
+				issues = append(issues, LintIssue{File: file, Line: line, Severity: severity, Message: fmt.Sprintf(format, args...)})
+			}
+
+			for _, marker := range stmt.invalidMarkers() {
+				issue(LintError, "invalid marker %q, use %s<rule> (reason) with rule %s or %s",
+					marker, LintAllowMarker, lintRuleDestructive, lintRuleVersionTable)
+			}
+			if matchesAny(stmt.sql, nonTransactionalPatterns) {
+				if len(statements) > 1 {
+					issue(LintError, "statement cannot run in a transaction, move it to a separate migration")
+				} else if !disableTx {
+					issue(LintError, "statement cannot run in a transaction, add ---- tern: disable-tx ----")
+				}
+			}
+			if !section.revert && matchesAny(stmt.sql, destructivePatterns) && !stmt.allows(lintRuleDestructive) {
+				issue(LintWarning, "destructive statement, confirm it by %s%s (reason)", LintAllowMarker, lintRuleDestructive)
+			}
+			if versionTablePattern.MatchString(stmt.sql) && !stmt.allows(lintRuleVersionTable) {
+				issue(LintWarning, "statement depends on the tern version table, confirm it by %s%s (reason)", LintAllowMarker, lintRuleVersionTable)
+			}
+		}
+	}
+	return issues
+}
+
+func matchesAny(sql string, patterns []*regexp.Regexp) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(sql) {
+			return true
+		}
+	}
+	return false
+}
+
+// statement is a single SQL statement without comments, the comments preceding it are kept separately
+type statement struct {
+	sql      string
+	comments []string
+	// line of the statement start in the section, starting with 1
+	line int
+}
+
+// allows returns true when the statement is preceded by a valid marker of the rule
+func (s statement) allows(rule string) bool {
+	for _, comment := range s.comments {
+		if parseAllowMarker(comment) == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// invalidMarkers returns markers without a known rule or a reason
+func (s statement) invalidMarkers() []string {
+	var invalid []string
+	for _, comment := range s.comments {
+		if strings.HasPrefix(comment, strings.TrimSpace(LintAllowMarker)) && parseAllowMarker(comment) == "" {
+			invalid = append(invalid, comment)
+		}
+	}
+	return invalid
+}
+
+// parseAllowMarker returns the rule of a valid marker, empty string otherwise
+func parseAllowMarker(comment string) string {
+	if !strings.HasPrefix(comment, LintAllowMarker) {
+		return ""
+	}
+	match := allowMarkerPattern.FindStringSubmatch(strings.TrimSpace(strings.TrimPrefix(comment, LintAllowMarker)))
+	if match == nil || (match[1] != lintRuleDestructive && match[1] != lintRuleVersionTable) {
+		return ""
+	}
+	return match[1]
+}
+
+// splitStatements splits SQL by semicolons outside of comments, quotes and dollar quoted strings.
+func splitStatements(sql string) []statement {
+	var result []statement
+	var current statement
+	var sb strings.Builder
+	line := 1
+
+	flush := func() {
+		current.sql = strings.TrimSpace(sb.String())
+		if current.sql != "" {
+			result = append(result, current)
+		}
+		current = statement{}
+		sb.Reset()
+	}
+	write := func(text string) {
+		if current.line == 0 && strings.TrimSpace(text) != "" {
+			current.line = line
+		}
+		sb.WriteString(text)
+	}
+
+	for i := 0; i < len(sql); {
+		rest := sql[i:]
+		switch {
+		case strings.HasPrefix(rest, "--"):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			current.comments = append(current.comments, strings.TrimSpace(rest[:end]))
+			i += end
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest, "*/")
+			if end < 0 {
+				end = len(rest) - 2
+			}
+			line += strings.Count(rest[:end+2], "\n")
+			i += end + 2
+		case rest[0] == '\'' || rest[0] == '"':
+			end := strings.IndexByte(rest[1:], rest[0]) + 2
+			if end < 2 {
+				end = len(rest)
+			}
+			write(rest[:end])
+			line += strings.Count(rest[:end], "\n")
+			i += end
+		case rest[0] == '$':
+			tag := dollarTagPattern.FindString(rest)
+			if tag == "" {
+				write("$")
+				i++
+				continue
+			}
+			end := strings.Index(rest[len(tag):], tag)
+			if end < 0 {
+				end = len(rest) - 2*len(tag)
+			}
+			quoted := rest[:end+2*len(tag)]
+			write(quoted)
+			line += strings.Count(quoted, "\n")
+			i += len(quoted)
+		case rest[0] == ';':
+			flush()
+			i++
+		default:
+			if rest[0] == '\n' {
+				line++
+			}
+			write(rest[:1])
+			i++
+		}
+	}
+	flush()
+	return result
+}
+
+var dollarTagPattern = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z0-9_]*)?\$`)
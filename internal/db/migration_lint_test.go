@@ -0,0 +1,101 @@
+package db_test
+
+import (
+	"consoledot-go-template/internal/db"
+	"consoledot-go-template/internal/db/migrations"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lint(t *testing.T, files map[string]string) []string {
+	t.Helper()
+	fsys := fstest.MapFS{}
+	for name, content := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(content)}
+	}
+	issues, err := db.LintMigrations(fsys)
+	require.NoError(t, err)
+
+	result := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		result = append(result, issue.String())
+	}
+	return result
+}
+
+func TestLintMigrations(t *testing.T) {
+	t.Run("embedded migrations", func(t *testing.T) {
+		issues, err := db.LintMigrations(migrations.EmbeddedSQLMigrations)
+		require.NoError(t, err)
+		assert.Empty(t, issues)
+	})
+
+	t.Run("numbering", func(t *testing.T) {
+		issues := lint(t, map[string]string{
+			"001_accounts.sql": "CREATE TABLE a (id INT);",
+			"001_hellos.sql":   "CREATE TABLE b (id INT);",
+			"003_index.sql":    "CREATE INDEX c ON b (id);",
+			"4-bad.sql":        "SELECT 1;",
+		})
+		assert.Equal(t, []string{
+			"4-bad.sql: error: name does not match NNN_lowercase_name.sql",
+			"001_accounts.sql: error: migration number 1 is used by 001_accounts.sql, 001_hellos.sql",
+			"002_*.sql: error: migration number is missing, numbering must be contiguous from 1",
+		}, issues)
+	})
+
+	t.Run("non-transactional statements", func(t *testing.T) {
+		issues := lint(t, map[string]string{
+			"001_mixed.sql":       "CREATE TABLE a (id INT);\n\nCREATE INDEX CONCURRENTLY a_id ON a (id);\n",
+			"002_without_tx.sql":  "CREATE UNIQUE INDEX CONCURRENTLY a_uid ON a (id);",
+			"003_disable_tx.sql":  "---- tern: disable-tx ----\nCREATE INDEX CONCURRENTLY a_idx ON a (id);",
+			"004_in_function.sql": "CREATE FUNCTION f() RETURNS void AS $$ BEGIN PERFORM 1; END; $$ LANGUAGE plpgsql;",
+		})
+		assert.Equal(t, []string{
+			"001_mixed.sql:3: error: statement cannot run in a transaction, move it to a separate migration",
+			"002_without_tx.sql:1: error: statement cannot run in a transaction, add ---- tern: disable-tx ----",
+		}, issues)
+	})
+
+	t.Run("destructive statements", func(t *testing.T) {
+		issues := lint(t, map[string]string{
+			"001_drop.sql": "-- the column is not used\nALTER TABLE a DROP COLUMN b;\n" +
+				"-- lint: allow destructive (replaced by c)\nALTER TABLE a DROP COLUMN c;\n" +
+				"DELETE FROM a;\n" +
+				"---- create above / drop below ----\n" +
+				"DROP TABLE a;\n",
+		})
+		assert.Equal(t, []string{
+			"001_drop.sql:2: warning: destructive statement, confirm it by -- lint: allow destructive (reason)",
+			"001_drop.sql:5: warning: destructive statement, confirm it by -- lint: allow destructive (reason)",
+		}, issues)
+	})
+
+	t.Run("markers without reason", func(t *testing.T) {
+		issues := lint(t, map[string]string{
+			"001_drop.sql": "-- lint: allow destructive\nALTER TABLE a DROP COLUMN b;\n" +
+				"-- lint: allow destructive ()\nALTER TABLE a DROP COLUMN c;\n" +
+				"-- lint: allow everything (just because)\nALTER TABLE a DROP COLUMN d;\n",
+		})
+		assert.Equal(t, []string{
+			`001_drop.sql:2: error: invalid marker "-- lint: allow destructive", use -- lint: allow <rule> (reason) with rule destructive or version-table`,
+			"001_drop.sql:2: warning: destructive statement, confirm it by -- lint: allow destructive (reason)",
+			`001_drop.sql:4: error: invalid marker "-- lint: allow destructive ()", use -- lint: allow <rule> (reason) with rule destructive or version-table`,
+			"001_drop.sql:4: warning: destructive statement, confirm it by -- lint: allow destructive (reason)",
+			`001_drop.sql:6: error: invalid marker "-- lint: allow everything (just because)", use -- lint: allow <rule> (reason) with rule destructive or version-table`,
+			"001_drop.sql:6: warning: destructive statement, confirm it by -- lint: allow destructive (reason)",
+		}, issues)
+	})
+
+	t.Run("version table", func(t *testing.T) {
+		issues := lint(t, map[string]string{
+			"001_trigger.sql": "/* tracks versions */\nCREATE TRIGGER t AFTER UPDATE ON schema_version EXECUTE PROCEDURE f();",
+		})
+		assert.Equal(t, []string{
+			"001_trigger.sql:2: warning: statement depends on the tern version table, confirm it by -- lint: allow version-table (reason)",
+		}, issues)
+	})
+}
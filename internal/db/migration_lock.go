@@ -0,0 +1,161 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"consoledot-go-template/internal/db/migrations"
+)
+
+var (
+	ErrMigrationLockTimeout = errors.New("timeout waiting for the migration lock")
+	ErrMigrationWaitTimeout = errors.New("timeout waiting for migrations")
+)
+
+// migrationLockClass is the first key of migration advisory locks, the second key identifies the schema
+const migrationLockClass = int32(7_391_005)
+
+// lockPollInterval is the delay between attempts to acquire the lock and between version checks
+var lockPollInterval = time.Second
+
+// migrationLockKey returns the second advisory lock key of the schema
+func migrationLockKey(schema string) int32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(schema))
+	return int32(h.Sum32())
+}
+
+// withMigrationLock calls the function while holding the advisory lock of the schema, so only one
+// process migrates the schema at a time. It fails when the lock is not acquired within the timeout,
+// zero timeout waits forever.
+func withMigrationLock(ctx context.Context, schema string, timeout time.Duration, fn func() error) error {
+	logger := log.Logger.With().Bool("migration", true).Str("schema", schema).Logger()
+	conn, err := Pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("error acquiring connection from the pool: %w", err)
+	}
+	defer conn.Release()
+
+	key := migrationLockKey(schema)
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	lastHolder := ""
+	for {
+		var locked bool
+		err = conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1, $2)", migrationLockClass, key).Scan(&locked)
+		if err != nil {
+			return fmt.Errorf("error acquiring migration lock: %w", err)
+		}
+		if locked {
+			break
+		}
+
+		if holder := migrationLockHolder(ctx, key); holder != lastHolder {
+			logger.Info().Msgf("Waiting for the migration lock held by %s", holder)
+			lastHolder = holder
+		}
+		select {
+		case <-deadline:
+			return fmt.Errorf("%w after %s", ErrMigrationLockTimeout, timeout)
+		case <-ctx.Done():
+			return fmt.Errorf("error acquiring migration lock: %w", ctx.Err())
+		case <-time.After(lockPollInterval):
+		}
+	}
+	logger.Debug().Msg("Acquired the migration lock")
+
+	defer func() {
+		// the context may be canceled already, the lock must be released before the connection is reused
+		_, unlockErr := conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1, $2)", migrationLockClass, key)
+		if unlockErr != nil {
+			logger.Warn().Err(unlockErr).Msg("Unable to release the migration lock, closing the connection")
+			_ = conn.Conn().Close(context.Background())
+		}
+	}()
+	return fn()
+}
+
+// migrationLockHolder describes the session holding the migration lock of the key
+func migrationLockHolder(ctx context.Context, key int32) string {
+	query := `-- name: MigrationLockHolder
+		SELECT a.pid, COALESCE(a.application_name, ''), COALESCE(host(a.client_addr), 'local'), a.backend_start
+		FROM pg_locks l JOIN pg_stat_activity a ON a.pid = l.pid
+		WHERE l.locktype = 'advisory' AND l.granted AND l.classid::bigint = $1 AND l.objid::bigint = $2 AND l.objsubid = 2`
+	var pid int32
+	var application, address string
+	var since time.Time
+	err := Pool.QueryRow(ctx, query, migrationLockClass, int64(uint32(key))).Scan(&pid, &application, &address, &since)
+	if err != nil {
+		return "an unknown process"
+	}
+	return fmt.Sprintf("pid %d (%s from %s) since %s", pid, application, address, since.UTC().Format(time.RFC3339))
+}
+
+// WaitForMigrations blocks until the schema is migrated at least to the version of the last
+// embedded migration, e.g. by the migration init container of another pod. It fails when
+// the version is not reached within the timeout, zero timeout waits forever.
+func WaitForMigrations(ctx context.Context, schema string, timeout time.Duration) error {
+	if schema == "" {
+		schema = "public"
+	}
+	logger := log.Logger.With().Bool("migration", true).Str("schema", schema).Logger()
+	expected := migrations.LatestVersion
+
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	last := int32(-1)
+	for {
+		current, err := currentMigrationVersion(ctx, schema)
+		if err != nil {
+			return err
+		}
+		if current >= expected {
+			logger.Info().Msgf("Database is migrated to version %d", current)
+			return nil
+		}
+
+		if current != last {
+			logger.Info().Msgf("Waiting for migrations, the version is %d of %d", current, expected)
+			last = current
+		}
+		select {
+		case <-deadline:
+			return fmt.Errorf("%w: version %d of %d after %s", ErrMigrationWaitTimeout, current, expected, timeout)
+		case <-ctx.Done():
+			return fmt.Errorf("error waiting for migrations: %w", ctx.Err())
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// currentMigrationVersion returns the version from the tern version table, zero when the table does not exist
+func currentMigrationVersion(ctx context.Context, schema string) (int32, error) {
+	table := fmt.Sprintf("%s.schema_version", schema)
+	var exists bool
+	if err := Pool.QueryRow(ctx, "SELECT to_regclass($1) IS NOT NULL", table).Scan(&exists); err != nil {
+		return 0, fmt.Errorf("error checking version table: %w", err)
+	}
+	if !exists {
+		return 0, nil
+	}
+
+	var version int32
+	if err := Pool.QueryRow(ctx, "SELECT version FROM "+table).Scan(&version); err != nil {
+		return 0, fmt.Errorf("error reading migration version: %w", err)
+	}
+	return version, nil
+}
@@ -0,0 +1,103 @@
+//go:build database
+// +build database
+
+package db
+
+import (
+	"consoledot-go-template/internal/db/migrations"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateUpConcurrently(t *testing.T) {
+	ctx := context.Background()
+	resetSchema(t)
+	defer resetSchema(t)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = MigrateUp(ctx, testSchema, -1)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	version, err := SchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, migrations.LatestVersion, version)
+
+	// every migration is recorded by the history trigger when it is applied
+	var applied, distinct int32
+	err = Pool.QueryRow(ctx, "SELECT COUNT(*), COUNT(DISTINCT version) FROM schema_migrations_history").Scan(&applied, &distinct)
+	require.NoError(t, err)
+	assert.Equal(t, migrations.LatestVersion, applied, "migrations must be applied exactly once")
+	assert.Equal(t, migrations.LatestVersion, distinct)
+}
+
+func TestMigrationLockTimeout(t *testing.T) {
+	ctx := context.Background()
+	interval := lockPollInterval
+	lockPollInterval = 10 * time.Millisecond
+	defer func() { lockPollInterval = interval }()
+
+	holder, err := Pool.Acquire(ctx)
+	require.NoError(t, err)
+	defer holder.Release()
+	_, err = holder.Exec(ctx, "SELECT pg_advisory_lock($1, $2)", migrationLockClass, migrationLockKey(testSchema))
+	require.NoError(t, err)
+	defer func() {
+		_, err := holder.Exec(ctx, "SELECT pg_advisory_unlock($1, $2)", migrationLockClass, migrationLockKey(testSchema))
+		assert.NoError(t, err)
+	}()
+
+	called := false
+	err = withMigrationLock(ctx, testSchema, 100*time.Millisecond, func() error {
+		called = true
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrMigrationLockTimeout)
+	assert.False(t, called, "function must not be called without the lock")
+
+	t.Run("other schemas are not locked", func(t *testing.T) {
+		err := withMigrationLock(ctx, "other", 100*time.Millisecond, func() error {
+			called = true
+			return nil
+		})
+		require.NoError(t, err)
+		assert.True(t, called)
+	})
+}
+
+func TestWaitForMigrations(t *testing.T) {
+	ctx := context.Background()
+	interval := lockPollInterval
+	lockPollInterval = 10 * time.Millisecond
+	defer func() { lockPollInterval = interval }()
+	resetSchema(t)
+	defer resetSchema(t)
+
+	t.Run("times out when the schema is behind", func(t *testing.T) {
+		require.NoError(t, MigrateUp(ctx, testSchema, 1))
+
+		err := WaitForMigrations(ctx, testSchema, 50*time.Millisecond)
+		assert.ErrorIs(t, err, ErrMigrationWaitTimeout)
+	})
+
+	t.Run("returns once the schema is migrated", func(t *testing.T) {
+		require.NoError(t, MigrateUp(ctx, testSchema, -1))
+
+		err := WaitForMigrations(ctx, testSchema, 50*time.Millisecond)
+		assert.NoError(t, err)
+	})
+}
@@ -5,7 +5,9 @@ import (
 	"embed"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
+	"regexp"
 	"strings"
 	"time"
 
@@ -13,20 +15,178 @@ import (
 	"github.com/jackc/tern/v2/migrate"
 	"github.com/rs/zerolog/log"
 
+	"consoledot-go-template/internal/config"
 	"consoledot-go-template/internal/db/migrations"
 )
 
 var (
 	ErrNoMigrationsFound = errors.New("no migrations found")
 	ErrMigration         = errors.New("unable to perform migration")
+	ErrMigrationVersion  = errors.New("invalid migration version")
 )
 
-// Migrate executes embedded SQL scripts from internal/db/migrations. For the time being
-// only "up" migrations are supported. When this package is initialized, the directory
-// is verified that it only contains XXX_*.up.sql files (XXX = numbers).
+// MigrationStatus is the state of a single embedded migration
+type MigrationStatus struct {
+	Version int32
+	Name    string
+	Applied bool
+	// AppliedAt is the last time the migration was applied, nil when it is unknown
+	AppliedAt *time.Time
+	// Reversible is true when the migration has the drop section
+	Reversible bool
+}
+
+// Migrate executes all pending embedded SQL scripts from internal/db/migrations.
 func Migrate(ctx context.Context, schema string) error {
-	logger := log.Logger.With().Bool("migration", true).Logger()
-	logger.Debug().Msg("Started migration")
+	return MigrateUp(ctx, schema, -1)
+}
+
+// MigrateUp executes the embedded SQL scripts from internal/db/migrations up to the version,
+// negative version applies all pending migrations.
+func MigrateUp(ctx context.Context, schema string, version int32) error {
+	return withLockedMigrator(ctx, schema, func(migrator *migrate.Migrator, current int32) error {
+		if version < 0 {
+			version = int32(len(migrator.Migrations))
+		}
+		if version < current {
+			return fmt.Errorf("%w: version %d is lower than the current version %d", ErrMigrationVersion, version, current)
+		}
+		return migrateTo(ctx, migrator, version)
+	})
+}
+
+// MigrateDown reverts the embedded SQL scripts by their drop sections down to the version,
+// negative version reverts the last applied migration.
+func MigrateDown(ctx context.Context, schema string, version int32) error {
+	return withLockedMigrator(ctx, schema, func(migrator *migrate.Migrator, current int32) error {
+		if version < 0 {
+			version = current - 1
+		}
+		if version < 0 || version > current {
+			return fmt.Errorf("%w: cannot migrate down from version %d to %d", ErrMigrationVersion, current, version)
+		}
+		return migrateTo(ctx, migrator, version)
+	})
+}
+
+// MigrateRedo reverts the last applied migration and applies it again.
+func MigrateRedo(ctx context.Context, schema string) error {
+	return withLockedMigrator(ctx, schema, func(migrator *migrate.Migrator, current int32) error {
+		if current == 0 {
+			return fmt.Errorf("%w: no migration has been applied", ErrMigrationVersion)
+		}
+		if err := migrateTo(ctx, migrator, current-1); err != nil {
+			return err
+		}
+		return migrateTo(ctx, migrator, current)
+	})
+}
+
+// MigrateDryRun writes the pending migrations up to the version with their SQL and executes
+// them in a transaction, which is always rolled back. Negative version plans all pending
+// migrations. It returns an error when any of the migrations fails to apply.
+func MigrateDryRun(ctx context.Context, schema string, version int32, w io.Writer) error {
+	return withMigrator(ctx, schema, func(migrator *migrate.Migrator, current int32) error {
+		if version < 0 {
+			version = int32(len(migrator.Migrations))
+		}
+		if version < current || version > int32(len(migrator.Migrations)) {
+			return fmt.Errorf("%w: cannot plan migration from version %d to %d", ErrMigrationVersion, current, version)
+		}
+		pending := migrator.Migrations[current:version]
+		if len(pending) == 0 {
+			_, err := fmt.Fprintf(w, "No pending migrations, the current version is %d\n", current)
+			return err
+		}
+
+		tx, err := Pool.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("error starting transaction: %w", err)
+		}
+		// the transaction is never committed
+		defer func() { _ = tx.Rollback(ctx) }()
+
+		for _, m := range pending {
+			fmt.Fprintf(w, "-- Migration %d %s\n%s\n\n", m.Sequence, m.Name, strings.TrimSpace(m.UpSQL))
+			if disableTxPattern.MatchString(m.UpSQL) {
+				return fmt.Errorf("%w %s: migrations without transaction cannot be verified", ErrMigration, m.Name)
+			}
+			if _, err = tx.Exec(ctx, m.UpSQL); err != nil {
+				var pgErr *pgconn.PgError
+				if errors.As(err, &pgErr) {
+					return fmt.Errorf("%w %s: %s", ErrMigration, m.Name, fmtDetailedError(m.UpSQL, pgErr))
+				}
+				return fmt.Errorf("%w %s: %s", ErrMigration, m.Name, err.Error())
+			}
+		}
+		_, err = fmt.Fprintf(w, "-- %d migration(s) applied cleanly and rolled back\n", len(pending))
+		return err
+	})
+}
+
+// disableTxPattern marks migrations tern runs without a transaction
+var disableTxPattern = regexp.MustCompile(`(?m)^---- tern: disable-tx ----$`)
+
+// MigrationStatuses returns the current version and the state of all embedded migrations.
+func MigrationStatuses(ctx context.Context, schema string) (int32, []MigrationStatus, error) {
+	var current int32
+	var result []MigrationStatus
+	err := withMigrator(ctx, schema, func(migrator *migrate.Migrator, version int32) error {
+		current = version
+		appliedAt, err := migrationsAppliedAt(ctx)
+		if err != nil {
+			return err
+		}
+
+		result = make([]MigrationStatus, 0, len(migrator.Migrations))
+		for _, m := range migrator.Migrations {
+			status := MigrationStatus{
+				Version:    m.Sequence,
+				Name:       m.Name,
+				Applied:    m.Sequence <= current,
+				Reversible: m.DownSQL != "",
+			}
+			if at, ok := appliedAt[m.Sequence]; ok && status.Applied {
+				status.AppliedAt = &at
+			}
+			result = append(result, status)
+		}
+		return nil
+	})
+	return current, result, err
+}
+
+// migrationsAppliedAt returns the last time each version was applied according to the history
+// table, which is created by the first migration.
+func migrationsAppliedAt(ctx context.Context) (map[int32]time.Time, error) {
+	result := make(map[int32]time.Time)
+	var exists bool
+	err := Pool.QueryRow(ctx, "SELECT to_regclass('schema_migrations_history') IS NOT NULL").Scan(&exists)
+	if err != nil || !exists {
+		return result, err
+	}
+
+	rows, err := Pool.Query(ctx, "SELECT version, MAX(applied_at) FROM schema_migrations_history GROUP BY version")
+	if err != nil {
+		return nil, fmt.Errorf("error querying schema history: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var version int32
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("error scanning schema history: %w", err)
+		}
+		result[version] = appliedAt.UTC()
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error scanning schema history: %w", err)
+	}
+	return result, nil
+}
+
+// withMigrator calls the function with a migrator of the embedded migrations and the current version.
+func withMigrator(ctx context.Context, schema string, fn func(migrator *migrate.Migrator, current int32) error) error {
 	if schema == "" {
 		schema = "public"
 	}
@@ -51,37 +211,45 @@ func Migrate(ctx context.Context, schema string) error {
 		return ErrNoMigrationsFound
 	}
 
-	err = migrator.Migrate(ctx)
+	current, err := migrator.GetCurrentVersion(ctx)
 	if err != nil {
-		var mgErr *migrate.MigrationPgError
-		var pgErr *pgconn.PgError
-		if errors.As(err, &mgErr) && errors.As(err, &pgErr) {
-			return fmt.Errorf("%w: %s", ErrMigration, fmtDetailedError(mgErr.Sql, pgErr))
-		} else {
-			return fmt.Errorf("unable to perform migration: %w", err)
-		}
+		return fmt.Errorf("error reading current version: %w", err)
+	}
+	return fn(migrator, current)
+}
+
+// withLockedMigrator is withMigrator holding the migration lock of the schema, the current
+// version is read after the lock is acquired.
+func withLockedMigrator(ctx context.Context, schema string, fn func(migrator *migrate.Migrator, current int32) error) error {
+	if schema == "" {
+		schema = "public"
 	}
+	return withMigrationLock(ctx, schema, config.Database.MigrationLockTimeout, func() error {
+		return withMigrator(ctx, schema, fn)
+	})
+}
 
-	// Print some additional info
-	rows, err := Pool.Query(ctx, "SELECT version, applied_at FROM schema_migrations_history")
-	if err != nil {
-		logger.Fatal().Err(err).Msg("Error querying schema history")
+func migrateTo(ctx context.Context, migrator *migrate.Migrator, version int32) error {
+	logger := log.Logger.With().Bool("migration", true).Logger()
+	migrator.OnStart = func(sequence int32, name, direction, _ string) {
+		logger.Info().Msgf("Migrating %s %d %s", direction, sequence, name)
 	}
-	defer rows.Close()
-	for rows.Next() {
-		var version int
-		var appliedAt time.Time
 
-		if err := rows.Scan(&version, &appliedAt); err != nil {
-			logger.Fatal().Err(err).Msg("Error scanning schema history")
+	err := migrator.MigrateTo(ctx, version)
+	if err != nil {
+		var mgErr migrate.MigrationPgError
+		var irreversibleErr migrate.IrreversibleMigrationError
+		var pgErr *pgconn.PgError
+		if errors.As(err, &mgErr) && errors.As(err, &pgErr) {
+			return fmt.Errorf("%w %s: %s", ErrMigration, mgErr.MigrationName, fmtDetailedError(mgErr.Sql, pgErr))
+		} else if errors.As(err, &irreversibleErr) {
+			return fmt.Errorf("%w: %s", ErrMigration, irreversibleErr.Error())
+		} else {
+			return fmt.Errorf("unable to perform migration: %w", err)
 		}
-		logger.Info().Msgf("Version %d was applied %v", version, appliedAt.UTC())
-	}
-	if err := rows.Err(); err != nil {
-		logger.Fatal().Err(err).Msg("Error scanning schema history")
 	}
 
-	logger.Info().Msgf("Finished with migration")
+	logger.Info().Msgf("Finished with migration to version %d", version)
 	return nil
 }
 
@@ -17,4 +17,12 @@ END;
 $$ language 'plpgsql';
 
 -- TRIGGER
-CREATE TRIGGER track_applied_migrations AFTER UPDATE ON schema_version FOR EACH ROW EXECUTE PROCEDURE track_applied_migration();
\ No newline at end of file
+-- lint: allow version-table (the history is recorded by tern's version updates)
+CREATE TRIGGER track_applied_migrations AFTER UPDATE ON schema_version FOR EACH ROW EXECUTE PROCEDURE track_applied_migration();
+
+---- create above / drop below ----
+
+-- lint: allow version-table (removes the history trigger created above)
+DROP TRIGGER track_applied_migrations ON schema_version;
+DROP FUNCTION track_applied_migration();
+DROP TABLE schema_migrations_history;
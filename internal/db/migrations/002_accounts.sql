@@ -3,4 +3,8 @@ CREATE TABLE accounts
   id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
   account_number TEXT UNIQUE,
   org_id TEXT NOT NULL UNIQUE
-);
\ No newline at end of file
+);
+
+---- create above / drop below ----
+
+DROP TABLE accounts;
@@ -4,4 +4,8 @@ CREATE TABLE hellos
   sender TEXT,
   recipient TEXT NOT NULL,
   message TEXT
-);
\ No newline at end of file
+);
+
+---- create above / drop below ----
+
+DROP TABLE hellos;
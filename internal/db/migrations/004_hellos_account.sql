@@ -0,0 +1,23 @@
+ALTER TABLE hellos
+  ADD COLUMN account_id BIGINT REFERENCES accounts (id) ON DELETE CASCADE;
+
+-- greetings recorded before multi-tenancy are kept under an account with empty org_id,
+-- identity.Decode rejects identities without org_id, so no request resolves to it
+INSERT INTO accounts (org_id)
+SELECT '' WHERE EXISTS (SELECT 1 FROM hellos)
+ON CONFLICT (org_id) DO NOTHING;
+
+UPDATE hellos SET account_id = (SELECT id FROM accounts WHERE org_id = '')
+WHERE account_id IS NULL;
+
+ALTER TABLE hellos ALTER COLUMN account_id SET NOT NULL;
+
+CREATE INDEX hellos_account_id ON hellos (account_id);
+
+---- create above / drop below ----
+
+DROP INDEX hellos_account_id;
+
+ALTER TABLE hellos DROP COLUMN account_id;
+
+DELETE FROM accounts WHERE org_id = '';
@@ -0,0 +1,24 @@
+-- record every applied migration, also when it is applied again after a rollback
+CREATE OR REPLACE FUNCTION track_applied_migration()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF new.version > old.version THEN
+        INSERT INTO schema_migrations_history(version) VALUES (new.version);
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+---- create above / drop below ----
+
+CREATE OR REPLACE FUNCTION track_applied_migration()
+RETURNS TRIGGER AS $$
+DECLARE _current_version integer;
+BEGIN
+    SELECT COALESCE(MAX(version),0) FROM schema_migrations_history INTO _current_version;
+    IF new.version > _current_version THEN
+        INSERT INTO schema_migrations_history(version) VALUES (new.version);
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
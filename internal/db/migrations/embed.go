@@ -1,6 +1,38 @@
 package migrations
 
-import "embed"
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
 
 //go:embed *.sql
 var EmbeddedSQLMigrations embed.FS
+
+// LatestVersion is the highest number of the embedded migrations, i.e. the schema
+// version the binary was built for.
+var LatestVersion = latestVersion(EmbeddedSQLMigrations)
+
+// latestVersion returns the highest numeric prefix of SQL files in the directory,
+// it panics when a file is not prefixed by a number.
+func latestVersion(fsys fs.FS) int32 {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		panic(fmt.Errorf("cannot list embedded migrations: %w", err))
+	}
+
+	var latest int32
+	for _, file := range files {
+		prefix, _, _ := strings.Cut(file, "_")
+		version, err := strconv.ParseInt(prefix, 10, 32)
+		if err != nil {
+			panic(fmt.Errorf("migration %s is not prefixed by its number: %w", file, err))
+		}
+		if int32(version) > latest {
+			latest = int32(version)
+		}
+	}
+	return latest
+}
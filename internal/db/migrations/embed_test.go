@@ -0,0 +1,79 @@
+package migrations
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func migrationFS(names ...string) fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for _, name := range names {
+		fsys[name] = &fstest.MapFile{Data: []byte("SELECT 1;")}
+	}
+	return fsys
+}
+
+func TestLatestVersion(t *testing.T) {
+	t.Run("embedded migrations", func(t *testing.T) {
+		files, err := fs.Glob(EmbeddedSQLMigrations, "*.sql")
+		require.NoError(t, err)
+
+		// migrations are numbered from 1 without gaps
+		assert.Equal(t, int32(len(files)), LatestVersion)
+	})
+
+	t.Run("highest number regardless of gaps and order", func(t *testing.T) {
+		fsys := migrationFS("010_later.sql", "002_accounts.sql", "009_hellos.sql", "README.md")
+		assert.Equal(t, int32(10), latestVersion(fsys))
+	})
+
+	t.Run("no migrations", func(t *testing.T) {
+		assert.Equal(t, int32(0), latestVersion(migrationFS()))
+	})
+
+	t.Run("file without number", func(t *testing.T) {
+		fsys := migrationFS("001_accounts.sql", "hellos.sql")
+		assert.Panics(t, func() { latestVersion(fsys) })
+	})
+}
+
+// upSQL returns statements of the migration section applied by tern, without comments
+// and blank lines, so only changes of the applied SQL are detected
+func upSQL(t *testing.T, name string) string {
+	t.Helper()
+	content, err := fs.ReadFile(EmbeddedSQLMigrations, name)
+	require.NoError(t, err)
+
+	up, _, _ := strings.Cut(string(content), "---- create above / drop below ----")
+	var lines []string
+	for _, line := range strings.Split(up, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "--") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestAppliedMigrationsUnchanged(t *testing.T) {
+	// deployed databases have applied these migrations and tern only records the version,
+	// changing them would make new databases differ, add a new migration instead
+	applied := map[string]string{
+		"001_migration_history.sql":         "37790efc",
+		"002_accounts.sql":                  "08113695",
+		"003_hellos.sql":                    "e3153338",
+		"004_hellos_account.sql":            "58d1a0f3",
+		"005_migration_history_reapply.sql": "109c270c",
+	}
+	for name, checksum := range applied {
+		sum := sha256.Sum256([]byte(upSQL(t, name)))
+		assert.Equal(t, checksum, hex.EncodeToString(sum[:4]), "up section of %s has changed", name)
+	}
+}
@@ -0,0 +1,82 @@
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CheckTimeout limits the duration of a single readiness check
+const CheckTimeout = 2 * time.Second
+
+// CheckFunc verifies a single dependency of the service. On success it may return
+// a short human readable detail (e.g. version of the dependency).
+type CheckFunc func(ctx context.Context) (string, error)
+
+// Result is the outcome of a single check
+type Result struct {
+	Name     string
+	Detail   string
+	Err      error
+	Duration time.Duration
+}
+
+var (
+	checksMu     sync.RWMutex
+	checks       = make(map[string]CheckFunc)
+	shuttingDown int32
+)
+
+// Register adds a named readiness check. Registering the same name twice replaces the check.
+func Register(name string, check CheckFunc) {
+	checksMu.Lock()
+	defer checksMu.Unlock()
+	checks[name] = check
+}
+
+// Unregister removes the named readiness check, e.g. when a test registered its own check.
+func Unregister(name string) {
+	checksMu.Lock()
+	defer checksMu.Unlock()
+	delete(checks, name)
+}
+
+// SetShuttingDown makes the service unready, it is called as soon as the service
+// is asked to terminate so the platform stops routing new requests to it.
+func SetShuttingDown() {
+	atomic.StoreInt32(&shuttingDown, 1)
+}
+
+// ShuttingDown returns true when the service is terminating
+func ShuttingDown() bool {
+	return atomic.LoadInt32(&shuttingDown) == 1
+}
+
+// RunChecks executes all registered checks concurrently and returns results sorted by name.
+func RunChecks(ctx context.Context) []Result {
+	checksMu.RLock()
+	defer checksMu.RUnlock()
+
+	results := make([]Result, 0, len(checks))
+	resultCh := make(chan Result, len(checks))
+	for name, check := range checks {
+		go func(name string, check CheckFunc) {
+			checkCtx, cancel := context.WithTimeout(ctx, CheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			detail, err := check(checkCtx)
+			resultCh <- Result{Name: name, Detail: detail, Err: err, Duration: time.Since(start)}
+		}(name, check)
+	}
+	for range checks {
+		results = append(results, <-resultCh)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
@@ -0,0 +1,47 @@
+package identity
+
+import (
+	"consoledot-go-template/internal/models"
+	"context"
+)
+
+type ctxKeyType int
+
+const (
+	identityCtxKey ctxKeyType = iota
+	accountCtxKey
+)
+
+// Identity returns the decoded x-rh-identity of the caller or nil when the request
+// was not authenticated.
+func Identity(ctx context.Context) *XRHID {
+	if value, ok := ctx.Value(identityCtxKey).(*XRHID); ok {
+		return value
+	}
+	return nil
+}
+
+func WithIdentity(ctx context.Context, id *XRHID) context.Context {
+	return context.WithValue(ctx, identityCtxKey, id)
+}
+
+// Account returns the account resolved from the caller identity or nil when
+// the request was not authenticated.
+func Account(ctx context.Context) *models.Account {
+	if value, ok := ctx.Value(accountCtxKey).(*models.Account); ok {
+		return value
+	}
+	return nil
+}
+
+// AccountID returns ID of the account resolved from the caller identity or 0.
+func AccountID(ctx context.Context) int64 {
+	if account := Account(ctx); account != nil {
+		return account.ID
+	}
+	return 0
+}
+
+func WithAccount(ctx context.Context, account *models.Account) context.Context {
+	return context.WithValue(ctx, accountCtxKey, account)
+}
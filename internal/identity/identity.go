@@ -0,0 +1,68 @@
+package identity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Header is the name of the HTTP header carrying base64 encoded identity
+// set by the platform gateway (3scale) for every authenticated request.
+const Header = "X-Rh-Identity"
+
+var (
+	ErrMissingIdentity = errors.New("missing identity header")
+	ErrInvalidIdentity = errors.New("invalid identity header")
+)
+
+// XRHID is the decoded x-rh-identity header
+type XRHID struct {
+	Identity Principal `json:"identity"`
+}
+
+// Principal is the identity of the caller, only fields the service needs are parsed.
+type Principal struct {
+	AccountNumber string   `json:"account_number"`
+	OrgID         string   `json:"org_id"`
+	Type          string   `json:"type"`
+	AuthType      string   `json:"auth_type"`
+	Internal      Internal `json:"internal"`
+}
+
+// Internal holds the legacy location of the organization ID
+type Internal struct {
+	OrgID string `json:"org_id"`
+}
+
+// Decode parses base64 encoded x-rh-identity header value and verifies that the identity
+// contains organization ID, which is the only mandatory tenant identifier.
+func Decode(header string) (*XRHID, error) {
+	if header == "" {
+		return nil, ErrMissingIdentity
+	}
+
+	data, err := base64.StdEncoding.DecodeString(header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to decode base64: %s", ErrInvalidIdentity, err.Error())
+	}
+
+	id := &XRHID{}
+	if err = json.Unmarshal(data, id); err != nil {
+		return nil, fmt.Errorf("%w: unable to parse JSON: %s", ErrInvalidIdentity, err.Error())
+	}
+
+	// older identities only carry the organization in the internal section
+	if id.Identity.OrgID == "" {
+		id.Identity.OrgID = id.Identity.Internal.OrgID
+	}
+	// empty org_id is reserved for the owner of greetings recorded before multi-tenancy
+	if id.Identity.OrgID == "" {
+		return nil, fmt.Errorf("%w: org_id is missing", ErrInvalidIdentity)
+	}
+	if id.Identity.Type == "" {
+		return nil, fmt.Errorf("%w: type is missing", ErrInvalidIdentity)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,55 @@
+package identity
+
+import (
+	"consoledot-go-template/internal/dao"
+	"consoledot-go-template/internal/logging"
+	"consoledot-go-template/internal/payloads"
+	"net/http"
+)
+
+// EnforceIdentity decodes the x-rh-identity header and stores it in the request context.
+// Requests without a valid identity are rejected.
+func EnforceIdentity(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := Decode(r.Header.Get(Header))
+		if err != nil {
+			renderError(w, r, payloads.NewAuthenticationError(r.Context(), "identity", err))
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithIdentity(r.Context(), id)))
+	})
+}
+
+// ResolveAccount finds or creates the account of the identity stored in the context
+// and stores it in the request context. It must be used after EnforceIdentity.
+func ResolveAccount(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := Identity(r.Context())
+		if id == nil {
+			renderError(w, r, payloads.NewAuthenticationError(r.Context(), "account", ErrMissingIdentity))
+			return
+		}
+
+		accountDao := dao.GetAccountDao(r.Context())
+		account, err := accountDao.GetOrCreateByIdentity(r.Context(), id.Identity.OrgID, id.Identity.AccountNumber)
+		if err != nil {
+			renderError(w, r, payloads.NewDAOError(r.Context(), "resolve account", err))
+			return
+		}
+
+		// decorate the request logger with the tenant identifiers
+		logger := logging.Logger(r.Context()).With().
+			Str("org_id", account.OrgID).
+			Int64("account_id", account.ID).
+			Logger()
+		ctx := logging.WithLogger(WithAccount(r.Context(), account), &logger)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func renderError(w http.ResponseWriter, r *http.Request, resp payloads.ErrorResponse) {
+	if errRender := payloads.RenderError(w, r, resp); errRender != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,82 @@
+package identity_test
+
+import (
+	"consoledot-go-template/internal/dao/stub"
+	"consoledot-go-template/internal/identity"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeIdentity(json string) string {
+	return base64.StdEncoding.EncodeToString([]byte(json))
+}
+
+func serveWithIdentity(ctx context.Context, t *testing.T, header string) (*httptest.ResponseRecorder, context.Context) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, "GET", "/api/template/hellos", nil)
+	require.NoError(t, err, "failed to create request")
+	if header != "" {
+		req.Header.Set(identity.Header, header)
+	}
+
+	var handlerCtx context.Context
+	handler := identity.EnforceIdentity(identity.ResolveAccount(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerCtx = r.Context()
+	})))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr, handlerCtx
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("falls back to internal org_id", func(t *testing.T) {
+		id, err := identity.Decode(encodeIdentity(`{"identity":{"type":"User","internal":{"org_id":"42"}}}`))
+		require.NoError(t, err)
+		assert.Equal(t, "42", id.Identity.OrgID)
+	})
+
+	t.Run("rejects identity without org_id", func(t *testing.T) {
+		_, err := identity.Decode(encodeIdentity(`{"identity":{"type":"User","account_number":"1"}}`))
+		assert.ErrorIs(t, err, identity.ErrInvalidIdentity)
+
+		// the account with empty org_id owns greetings recorded before multi-tenancy
+		_, err = identity.Decode(encodeIdentity(`{"identity":{"type":"User","org_id":"","internal":{"org_id":""}}}`))
+		assert.ErrorIs(t, err, identity.ErrInvalidIdentity)
+	})
+
+	t.Run("rejects invalid base64", func(t *testing.T) {
+		_, err := identity.Decode("not base64!")
+		assert.ErrorIs(t, err, identity.ErrInvalidIdentity)
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("rejects request without identity", func(t *testing.T) {
+		ctx := stub.WithAccountDao(context.Background())
+		rr, handlerCtx := serveWithIdentity(ctx, t, "")
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code, "Wrong status code")
+		assert.Nil(t, handlerCtx, "handler must not be called")
+	})
+
+	t.Run("resolves the same account for the same organization", func(t *testing.T) {
+		ctx := stub.WithAccountDao(context.Background())
+		header := encodeIdentity(`{"identity":{"type":"User","org_id":"13","account_number":"7"}}`)
+
+		rr, firstCtx := serveWithIdentity(ctx, t, header)
+		require.Equal(t, http.StatusOK, rr.Code, "Wrong status code")
+		_, secondCtx := serveWithIdentity(ctx, t, header)
+
+		account := identity.Account(firstCtx)
+		require.NotNil(t, account)
+		assert.Equal(t, "13", account.OrgID)
+		assert.Equal(t, "7", account.AccountNumber.String)
+		assert.Equal(t, account.ID, identity.AccountID(secondCtx))
+	})
+}
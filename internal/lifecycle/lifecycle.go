@@ -0,0 +1,181 @@
+// Package lifecycle coordinates the shutdown of the service. Components register shutdown
+// hooks into phases, hooks run phase by phase and in registration order within a phase.
+// The whole shutdown is limited by a hard-kill timeout and a second signal forces the exit.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ErrHooksFailed is returned when any of the shutdown hooks failed
+var ErrHooksFailed = errors.New("shutdown hooks failed")
+
+// Phase orders the shutdown hooks
+type Phase int
+
+const (
+	// PhaseStopAccepting makes the service unready and stops accepting new work
+	PhaseStopAccepting Phase = iota
+	// PhaseDrain waits for the work in progress, e.g. in-flight HTTP requests
+	PhaseDrain
+	// PhaseFlush flushes buffered data, e.g. traces and CloudWatch logs
+	PhaseFlush
+	// PhaseClose closes connections to dependencies, e.g. the database pool
+	PhaseClose
+)
+
+var phaseNames = map[Phase]string{
+	PhaseStopAccepting: "stop accepting",
+	PhaseDrain:         "drain",
+	PhaseFlush:         "flush",
+	PhaseClose:         "close",
+}
+
+func (p Phase) String() string {
+	return phaseNames[p]
+}
+
+// HookFunc is a shutdown hook, it should return when the context is done
+type HookFunc func(ctx context.Context) error
+
+type hook struct {
+	phase   Phase
+	name    string
+	timeout time.Duration
+	fn      HookFunc
+}
+
+// Manager runs the shutdown hooks, it is safe for concurrent use.
+type Manager struct {
+	timeout time.Duration
+	// exit terminates the process, tests replace it
+	exit func(code int)
+
+	mu     sync.Mutex
+	hooks  []hook
+	signal os.Signal
+
+	once sync.Once
+	done chan struct{}
+	err  error
+}
+
+// NewManager creates a manager which kills the process when the shutdown takes longer
+// than the timeout, zero timeout disables the hard kill.
+func NewManager(timeout time.Duration) *Manager {
+	return &Manager{
+		timeout: timeout,
+		exit:    os.Exit,
+		done:    make(chan struct{}),
+	}
+}
+
+// OnShutdown registers a hook into the phase. The context of the hook is canceled after
+// the timeout, zero timeout limits the hook only by the hard-kill timeout of the manager.
+func (m *Manager) OnShutdown(phase Phase, name string, timeout time.Duration, fn HookFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook{phase: phase, name: name, timeout: timeout, fn: fn})
+}
+
+// Signal returns the signal which started the shutdown, it is nil until a signal is received.
+func (m *Manager) Signal() os.Signal {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.signal
+}
+
+// WaitForSignal blocks until SIGINT or SIGTERM is received and then shuts down.
+// It returns an error when any of the hooks failed.
+func (m *Manager) WaitForSignal() error {
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	sig := <-sigs
+	log.Info().Msgf("Received %s, shutting down", sig)
+	m.mu.Lock()
+	m.signal = sig
+	m.mu.Unlock()
+
+	go func() {
+		select {
+		case sig := <-sigs:
+			log.Warn().Msgf("Received %s during shutdown, forcing exit", sig)
+			m.exit(1)
+		case <-m.done:
+		}
+	}()
+	return m.Shutdown()
+}
+
+// Fatal logs the error, shuts down and exits with non-zero code. Unlike log.Fatal, it runs
+// the shutdown hooks, e.g. it flushes logs and closes the database.
+func (m *Manager) Fatal(err error, msg string) {
+	log.Error().Err(err).Msg(msg)
+	_ = m.Shutdown()
+	m.exit(1)
+}
+
+// Shutdown runs all the hooks. Only the first call runs them, the other calls wait
+// for it to finish. It returns an error when any of the hooks failed.
+func (m *Manager) Shutdown() error {
+	m.once.Do(func() {
+		defer close(m.done)
+		if m.timeout > 0 {
+			killTimer := time.AfterFunc(m.timeout, func() {
+				log.Error().Msgf("Shutdown did not finish in %s, forcing exit", m.timeout)
+				m.exit(1)
+			})
+			defer killTimer.Stop()
+		}
+		m.err = m.runHooks()
+	})
+	<-m.done
+	return m.err
+}
+
+func (m *Manager) runHooks() error {
+	m.mu.Lock()
+	hooks := make([]hook, len(m.hooks))
+	copy(hooks, m.hooks)
+	m.mu.Unlock()
+	sort.SliceStable(hooks, func(i, j int) bool {
+		return hooks[i].phase < hooks[j].phase
+	})
+
+	var failed []string
+	for _, h := range hooks {
+		logger := log.With().Str("phase", h.phase.String()).Str("hook", h.name).Logger()
+		logger.Debug().Msg("Running shutdown hook")
+
+		if err := runHook(h); err != nil {
+			logger.Error().Err(err).Msg("Shutdown hook failed")
+			failed = append(failed, h.name)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%w: %v", ErrHooksFailed, failed)
+	}
+	return nil
+}
+
+func runHook(h hook) error {
+	ctx := context.Background()
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+	return h.fn(ctx)
+}
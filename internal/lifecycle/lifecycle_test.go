@@ -0,0 +1,158 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestManager returns a manager reporting exit codes to the channel instead of exiting
+func newTestManager(timeout time.Duration) (*Manager, chan int) {
+	exits := make(chan int, 10)
+	m := NewManager(timeout)
+	m.exit = func(code int) { exits <- code }
+	return m, exits
+}
+
+func TestShutdown(t *testing.T) {
+	t.Run("runs hooks by phases in registration order", func(t *testing.T) {
+		m, _ := newTestManager(0)
+		var order []string
+		record := func(name string) HookFunc {
+			return func(_ context.Context) error {
+				order = append(order, name)
+				return nil
+			}
+		}
+		m.OnShutdown(PhaseFlush, "logs", 0, record("logs"))
+		m.OnShutdown(PhaseClose, "database", 0, record("database"))
+		m.OnShutdown(PhaseDrain, "api", 0, record("api"))
+		m.OnShutdown(PhaseStopAccepting, "readiness", 0, record("readiness"))
+		m.OnShutdown(PhaseDrain, "metrics", 0, record("metrics"))
+
+		require.NoError(t, m.Shutdown())
+		assert.Equal(t, []string{"readiness", "api", "metrics", "logs", "database"}, order)
+	})
+
+	t.Run("limits hooks by their timeout", func(t *testing.T) {
+		m, _ := newTestManager(0)
+		m.OnShutdown(PhaseDrain, "slow", 10*time.Millisecond, func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		assert.ErrorIs(t, m.Shutdown(), ErrHooksFailed)
+	})
+
+	t.Run("runs all hooks when one fails", func(t *testing.T) {
+		m, _ := newTestManager(0)
+		closed := false
+		m.OnShutdown(PhaseDrain, "api", 0, func(_ context.Context) error { return errors.New("failed") })
+		m.OnShutdown(PhaseClose, "database", 0, func(_ context.Context) error {
+			closed = true
+			return nil
+		})
+
+		err := m.Shutdown()
+		assert.ErrorIs(t, err, ErrHooksFailed)
+		assert.Contains(t, err.Error(), "api")
+		assert.True(t, closed)
+	})
+
+	t.Run("runs hooks once", func(t *testing.T) {
+		m, _ := newTestManager(0)
+		calls := 0
+		m.OnShutdown(PhaseClose, "database", 0, func(_ context.Context) error {
+			calls++
+			return nil
+		})
+
+		require.NoError(t, m.Shutdown())
+		require.NoError(t, m.Shutdown())
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("kills the process after the timeout", func(t *testing.T) {
+		m, exits := newTestManager(10 * time.Millisecond)
+		release := make(chan struct{})
+		m.OnShutdown(PhaseDrain, "stuck", 0, func(_ context.Context) error {
+			<-release
+			return nil
+		})
+
+		go func() { _ = m.Shutdown() }()
+		select {
+		case code := <-exits:
+			assert.Equal(t, 1, code)
+		case <-time.After(time.Second):
+			t.Error("the process was not killed")
+		}
+		close(release)
+	})
+}
+
+func TestWaitForSignal(t *testing.T) {
+	// keeps the test process alive when a signal is sent before WaitForSignal is listening
+	ignored := make(chan os.Signal, 10)
+	signal.Notify(ignored, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(ignored)
+
+	t.Run("shuts down on signal", func(t *testing.T) {
+		m, _ := newTestManager(0)
+		ran := make(chan struct{})
+		m.OnShutdown(PhaseClose, "database", 0, func(_ context.Context) error {
+			close(ran)
+			return nil
+		})
+
+		result := make(chan error)
+		go func() { result <- m.WaitForSignal() }()
+		sendSignal(t, m, syscall.SIGTERM, ran)
+
+		require.NoError(t, <-result)
+		assert.Equal(t, syscall.SIGTERM, m.Signal())
+	})
+
+	t.Run("second signal forces exit", func(t *testing.T) {
+		m, exits := newTestManager(0)
+		started, release := make(chan struct{}), make(chan struct{})
+		m.OnShutdown(PhaseDrain, "stuck", 0, func(_ context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+
+		go func() { _ = m.WaitForSignal() }()
+		sendSignal(t, m, syscall.SIGINT, started)
+		require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGINT))
+
+		select {
+		case code := <-exits:
+			assert.Equal(t, 1, code)
+		case <-time.After(time.Second):
+			t.Error("the process did not exit")
+		}
+		close(release)
+	})
+}
+
+// sendSignal sends the signal until the manager starts the shutdown, the first signal
+// can be sent before WaitForSignal is listening.
+func sendSignal(t *testing.T, m *Manager, sig syscall.Signal, started chan struct{}) {
+	t.Helper()
+	for {
+		require.NoError(t, syscall.Kill(syscall.Getpid(), sig))
+		select {
+		case <-started:
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
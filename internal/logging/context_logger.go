@@ -9,7 +9,11 @@ import (
 
 type ctxKeyType int
 
-const loggerCtxKey ctxKeyType = iota
+const (
+	loggerCtxKey ctxKeyType = iota
+	requestIDCtxKey
+	traceIDCtxKey
+)
 
 // Logger returns the main logger with context fields or the standard global logger
 // when the main logger was not set. Never returns nil.
@@ -24,3 +28,27 @@ func Logger(ctx context.Context) *zerolog.Logger {
 func WithLogger(ctx context.Context, logger *zerolog.Logger) context.Context {
 	return context.WithValue(ctx, loggerCtxKey, logger)
 }
+
+// RequestID returns ID of the request being served or an empty string.
+func RequestID(ctx context.Context) string {
+	if value, ok := ctx.Value(requestIDCtxKey).(string); ok {
+		return value
+	}
+	return ""
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDCtxKey, requestID)
+}
+
+// TraceID returns W3C trace ID of the request being served or an empty string.
+func TraceID(ctx context.Context) string {
+	if value, ok := ctx.Value(traceIDCtxKey).(string); ok {
+		return value
+	}
+	return ""
+}
+
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDCtxKey, traceID)
+}
@@ -1,14 +1,15 @@
 package logging
 
 import (
+	"consoledot-go-template/internal/metrics"
 	"net/http"
 	"runtime/debug"
 	"strconv"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/rs/zerolog"
-	"github.com/rs/zerolog/log"
 )
 
 func NewMiddleware(globalLogger zerolog.Logger) func(next http.Handler) http.Handler {
@@ -21,9 +22,16 @@ func NewMiddleware(globalLogger zerolog.Logger) func(next http.Handler) http.Han
 				Str("remote_ip", r.RemoteAddr).
 				Str("url", r.URL.Path).
 				Str("method", r.Method)
+			if requestID := RequestID(r.Context()); requestID != "" {
+				loggerCtx = loggerCtx.Str("request_id", requestID)
+			}
+			if traceID := TraceID(r.Context()); traceID != "" {
+				loggerCtx = loggerCtx.Str("trace_id", traceID)
+			}
 			contextLogger := loggerCtx.Logger()
 			contextLogger.Debug().Msgf("Started %s request %s", r.Method, r.URL.Path)
 
+			metrics.HTTPRequestStarted()
 			t1 := time.Now()
 			defer func() {
 				duration := time.Since(t1)
@@ -34,18 +42,30 @@ func NewMiddleware(globalLogger zerolog.Logger) func(next http.Handler) http.Han
 					Logger()
 
 				// prevent the application from exiting
+				status := ww.Status()
 				if rec := recover(); rec != nil {
 					afterLogger.Error().
 						Bool("panic", true).
 						Int("status", http.StatusInternalServerError).
 						Msgf("Unhandled panic: %s\n%s", rec, debug.Stack())
 					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+					status = http.StatusInternalServerError
+				} else if status == 0 {
+					// nothing was written, net/http responds with 200
+					status = http.StatusOK
 				}
 
-				log.Info().
-					Int("status", ww.Status()).
+				// the route pattern is known only after the request was routed
+				var route string
+				if rctx := chi.RouteContext(r.Context()); rctx != nil {
+					route = rctx.RoutePattern()
+				}
+				metrics.ObserveHTTPRequest(route, r.Method, status, duration, ww.BytesWritten())
+
+				afterLogger.Info().
+					Int("status", status).
 					Msgf("Completed %s request %s in %s with %d",
-						r.Method, r.URL.Path, duration.Round(time.Millisecond).String(), ww.Status())
+						r.Method, r.URL.Path, duration.Round(time.Millisecond).String(), status)
 			}()
 
 			ctx := WithLogger(r.Context(), &contextLogger)
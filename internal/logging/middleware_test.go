@@ -0,0 +1,36 @@
+package logging_test
+
+import (
+	"bytes"
+	"consoledot-go-template/internal/logging"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Run("logs status of recovered panic", func(t *testing.T) {
+		var out bytes.Buffer
+		handler := logging.NewMiddleware(zerolog.New(&out))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		}))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+		require.Equal(t, http.StatusInternalServerError, rr.Code, "Wrong status code")
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		var completed struct {
+			Status  int    `json:"status"`
+			Message string `json:"message"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &completed))
+		assert.Equal(t, http.StatusInternalServerError, completed.Status)
+		assert.True(t, strings.HasSuffix(completed.Message, "with 500"), completed.Message)
+	})
+}
@@ -0,0 +1,30 @@
+package logging
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+const RequestIDHeader = "X-Request-Id"
+
+// IDs coming from clients end up in logs, only short and safe values are accepted
+var requestIDRegexp = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestIDMiddleware accepts the request ID sent by the client (or gateway) or
+// generates a new one, and stores it in the request context. The request ID is echoed
+// back in the response. The trace ID is stored by telemetry.Middleware. It must be used
+// before the logging middleware so IDs are added to the request logger.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if !requestIDRegexp.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+		ctx := WithRequestID(r.Context(), requestID)
+
+		w.Header().Set(RequestIDHeader, requestID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
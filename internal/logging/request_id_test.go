@@ -0,0 +1,46 @@
+package logging_test
+
+import (
+	"consoledot-go-template/internal/logging"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveWithHeaders(t *testing.T, headers map[string]string) (*httptest.ResponseRecorder, context.Context) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), "GET", "/", nil)
+	require.NoError(t, err, "failed to create request")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	var handlerCtx context.Context
+	handler := logging.RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerCtx = r.Context()
+	}))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr, handlerCtx
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	t.Run("accepts request ID from the client", func(t *testing.T) {
+		rr, ctx := serveWithHeaders(t, map[string]string{logging.RequestIDHeader: "abc-123"})
+
+		assert.Equal(t, "abc-123", logging.RequestID(ctx))
+		assert.Equal(t, "abc-123", rr.Header().Get(logging.RequestIDHeader))
+	})
+
+	t.Run("replaces invalid request ID", func(t *testing.T) {
+		rr, ctx := serveWithHeaders(t, map[string]string{logging.RequestIDHeader: "bad id\n"})
+
+		assert.NotEqual(t, "bad id\n", logging.RequestID(ctx))
+		assert.Len(t, logging.RequestID(ctx), 36)
+		assert.Equal(t, logging.RequestID(ctx), rr.Header().Get(logging.RequestIDHeader))
+	})
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// UnmatchedRoute is the route label for requests which did not match any route,
+// the raw URL path is never used as a label to keep the cardinality low.
+const UnmatchedRoute = "unmatched"
+
+var (
+	httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Number of HTTP requests by route, method and status code",
+	}, []string{"route", "method", "status"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of HTTP requests by route and method",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	httpRequestsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "http_requests_in_flight",
+		Help: "Number of HTTP requests currently being served",
+	})
+
+	httpResponseSize = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_response_size_bytes",
+		Help:    "Size of HTTP responses by route and method",
+		Buckets: prometheus.ExponentialBuckets(64, 4, 8),
+	}, []string{"route", "method"})
+)
+
+// HTTPRequestStarted increases the number of in-flight requests, every call must be
+// followed by ObserveHTTPRequest.
+func HTTPRequestStarted() {
+	httpRequestsInFlight.Inc()
+}
+
+// ObserveHTTPRequest records a finished request, route is the chi route pattern.
+func ObserveHTTPRequest(route, method string, status int, duration time.Duration, bytesOut int) {
+	if route == "" {
+		route = UnmatchedRoute
+	}
+	httpRequestsInFlight.Dec()
+	httpRequestsTotal.WithLabelValues(route, method, strconv.Itoa(status)).Inc()
+	httpRequestDuration.WithLabelValues(route, method).Observe(duration.Seconds())
+	httpResponseSize.WithLabelValues(route, method).Observe(float64(bytesOut))
+}
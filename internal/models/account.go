@@ -0,0 +1,10 @@
+package models
+
+import "database/sql"
+
+// Account represents a tenant identified by the x-rh-identity header
+type Account struct {
+	ID            int64          `db:"id"`
+	AccountNumber sql.NullString `db:"account_number"`
+	OrgID         string         `db:"org_id"`
+}
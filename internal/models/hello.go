@@ -2,8 +2,9 @@ package models
 
 // Hello represents message from one person to another
 type Hello struct {
-	ID      int64  `db:"id"`
-	From    string `db:"sender"`
-	To      string `db:"recipient"`
-	Message string `db:"message"`
+	ID        int64  `db:"id"`
+	AccountID int64  `db:"account_id"`
+	From      string `db:"sender"`
+	To        string `db:"recipient"`
+	Message   string `db:"message"`
 }
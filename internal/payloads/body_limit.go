@@ -0,0 +1,53 @@
+package payloads
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrRequestTooLarge is returned when reading a request body exceeding the limit
+var ErrRequestTooLarge = errors.New("request body too large")
+
+// LimitBody is a middleware limiting size of request bodies by http.MaxBytesReader.
+// Reading more than maxBytes fails with ErrRequestTooLarge, which is rendered as 413
+// by NewInvalidRequestError.
+func LimitBody(maxBytes int64) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > maxBytes {
+				message := fmt.Sprintf("body exceeds %d bytes", maxBytes)
+				renderError(w, r, NewRequestTooLargeError(r.Context(), message, ErrRequestTooLarge))
+				return
+			}
+			if r.Body != nil && r.Body != http.NoBody {
+				r.Body = &limitedBody{ReadCloser: http.MaxBytesReader(w, r.Body, maxBytes), limit: maxBytes}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// limitedBody translates the error of http.MaxBytesReader, which is not exported in Go 1.18,
+// to ErrRequestTooLarge. The reader fails only after reading the whole limit.
+type limitedBody struct {
+	io.ReadCloser
+	limit int64
+	read  int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if err != nil && !errors.Is(err, io.EOF) && b.read >= b.limit {
+		return n, ErrRequestTooLarge
+	}
+	return n, err
+}
+
+func renderError(w http.ResponseWriter, r *http.Request, resp ErrorResponse) {
+	if err := RenderError(w, r, resp); err != nil {
+		http.Error(w, resp.Message, resp.HTTPStatusCode)
+	}
+}
@@ -0,0 +1,47 @@
+package payloads_test
+
+import (
+	"consoledot-go-template/internal/payloads"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitBody(t *testing.T) {
+	handler := payloads.LimitBody(10)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			require.NoError(t, payloads.RenderError(w, r, payloads.NewInvalidRequestError(r.Context(), "read body", err)))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	serve := func(body io.Reader, contentLength int64) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/hellos", body)
+		req.ContentLength = contentLength
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("body within the limit", func(t *testing.T) {
+		rr := serve(strings.NewReader("0123456789"), 10)
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("declared content length over the limit", func(t *testing.T) {
+		rr := serve(strings.NewReader("0123456789a"), 11)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"code":"request_too_large"`)
+	})
+
+	t.Run("chunked body over the limit", func(t *testing.T) {
+		rr := serve(strings.NewReader("0123456789a"), -1)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	})
+}
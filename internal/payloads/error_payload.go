@@ -1,22 +1,93 @@
 package payloads
 
 import (
+	"consoledot-go-template/internal/config"
+	"consoledot-go-template/internal/dao"
 	"consoledot-go-template/internal/logging"
+	"consoledot-go-template/internal/validation"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/render"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// ErrorCode is a stable machine-readable identification of the error, clients
+// can rely on it unlike on the messages.
+type ErrorCode string
+
+const (
+	ErrorCodeInvalidRequest ErrorCode = "invalid_request"
+	ErrorCodeAuthentication ErrorCode = "authentication_error"
+	ErrorCodeNotFound       ErrorCode = "not_found"
+	ErrorCodeTooLarge       ErrorCode = "request_too_large"
+	ErrorCodeConflict       ErrorCode = "conflict"
+	ErrorCodeConstraint     ErrorCode = "constraint_violation"
+	ErrorCodeDAO            ErrorCode = "dao_error"
+	ErrorCodeUnavailable    ErrorCode = "service_unavailable"
+	ErrorCodeTimeout        ErrorCode = "timeout"
+	ErrorCodeRender         ErrorCode = "render_error"
+)
+
+// errorTitles are short summaries of the error codes used as problem titles
+var errorTitles = map[ErrorCode]string{
+	ErrorCodeInvalidRequest: "Invalid request",
+	ErrorCodeAuthentication: "Authentication error",
+	ErrorCodeNotFound:       "Not found",
+	ErrorCodeTooLarge:       "Request too large",
+	ErrorCodeConflict:       "Conflict",
+	ErrorCodeConstraint:     "Constraint violation",
+	ErrorCodeDAO:            "DAO error",
+	ErrorCodeUnavailable:    "Service unavailable",
+	ErrorCodeTimeout:        "Timeout",
+	ErrorCodeRender:         "Rendering error",
+}
+
+// ProblemContentType is the media type of RFC 7807 problem details. Clients opt in
+// to the problem format by accepting it.
+const ProblemContentType = "application/problem+json"
+
+// problemTypePrefix makes the error code a URI for the problem type
+const problemTypePrefix = "urn:template-api:problem:"
+
 // ErrorResponse is used as a payload for all errors
 type ErrorResponse struct {
 	// HTTP status code
 	HTTPStatusCode int `json:"-"`
+	// stable error code
+	Code ErrorCode `json:"code"`
 	// user facing error message
 	Message string `json:"msg"`
-	// full root cause
-	Error string `json:"error"`
+	// full root cause, not exposed for server and database errors outside development mode
+	Error string `json:"error,omitempty"`
+	// request ID for correlation with logs
+	RequestID string `json:"request_id,omitempty"`
+	// invalid fields of the request payload
+	Fields []validation.FieldError `json:"fields,omitempty"`
+}
+
+// ProblemResponse is the RFC 7807 representation of ErrorResponse
+type ProblemResponse struct {
+	// URI identifying the problem type
+	Type string `json:"type"`
+	// short summary of the problem type
+	Title string `json:"title"`
+	// HTTP status code
+	Status int `json:"status"`
+	// explanation of this occurrence of the problem
+	Detail string `json:"detail,omitempty"`
+	// path of the request
+	Instance string `json:"instance,omitempty"`
+	// stable error code
+	Code ErrorCode `json:"code"`
+	// request ID for correlation with logs
+	RequestID string `json:"request_id,omitempty"`
+	// invalid fields of the request payload
+	Fields []validation.FieldError `json:"fields,omitempty"`
 }
 
 func (e ErrorResponse) Render(_ http.ResponseWriter, r *http.Request) error {
@@ -24,31 +95,144 @@ func (e ErrorResponse) Render(_ http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
-func newErrorResponse(ctx context.Context, status int, userMsg string, err error) ErrorResponse {
-	logging.Logger(ctx).Err(err).Msg(userMsg)
-	return ErrorResponse{
+// Problem converts the error response to problem details of the request
+func (e ErrorResponse) Problem(r *http.Request) ProblemResponse {
+	detail := e.Message
+	if e.Error != "" {
+		detail = fmt.Sprintf("%s: %s", e.Message, e.Error)
+	}
+	return ProblemResponse{
+		Type:      problemTypePrefix + string(e.Code),
+		Title:     errorTitles[e.Code],
+		Status:    e.HTTPStatusCode,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		Code:      e.Code,
+		RequestID: e.RequestID,
+		Fields:    e.Fields,
+	}
+}
+
+// RenderError writes the error response, as problem details when the client accepts them.
+func RenderError(w http.ResponseWriter, r *http.Request, resp ErrorResponse) error {
+	if !acceptsProblem(r) {
+		return render.Render(w, r, resp)
+	}
+
+	body, err := json.Marshal(resp.Problem(r))
+	if err != nil {
+		return fmt.Errorf("cannot marshal problem: %w", err)
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(resp.HTTPStatusCode)
+	_, err = w.Write(body)
+	return err
+}
+
+func acceptsProblem(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, mediaType := range strings.Split(accept, ",") {
+			mediaType, _, _ = strings.Cut(mediaType, ";")
+			if strings.TrimSpace(mediaType) == ProblemContentType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func newErrorResponse(ctx context.Context, status int, code ErrorCode, userMsg string, err error) ErrorResponse {
+	logging.Logger(ctx).Err(err).Str("code", string(code)).Msg(userMsg)
+	resp := ErrorResponse{
 		HTTPStatusCode: status,
+		Code:           code,
 		Message:        userMsg,
-		Error:          err.Error(),
+		RequestID:      logging.RequestID(ctx),
+	}
+
+	// root causes of server and database errors are internal (e.g. constraint names), they are logged instead
+	if config.Application.Development || (status < http.StatusInternalServerError && !isInternalError(err)) {
+		resp.Error = err.Error()
 	}
+
+	var fieldErrors validation.Errors
+	if errors.As(err, &fieldErrors) {
+		resp.Fields = fieldErrors
+	}
+	return resp
 }
 
+// isInternalError returns true for database errors, which are classified as client errors
+// (e.g. 409 for a duplicate key), but their messages reveal the schema.
+func isInternalError(err error) bool {
+	var daoErr *dao.Error
+	var pgErr *pgconn.PgError
+	return errors.As(err, &daoErr) || errors.As(err, &pgErr)
+}
+
+// NewInvalidRequestError returns 400 error response, bodies exceeding the limit (see LimitBody)
+// result in 413.
 func NewInvalidRequestError(ctx context.Context, message string, err error) ErrorResponse {
+	if errors.Is(err, ErrRequestTooLarge) {
+		return NewRequestTooLargeError(ctx, message, err)
+	}
 	message = fmt.Sprintf("Invalid request: %s", message)
-	return newErrorResponse(ctx, http.StatusBadRequest, message, err)
+	return newErrorResponse(ctx, http.StatusBadRequest, ErrorCodeInvalidRequest, message, err)
+}
+
+func NewRequestTooLargeError(ctx context.Context, message string, err error) ErrorResponse {
+	message = fmt.Sprintf("Request too large: %s", message)
+	return newErrorResponse(ctx, http.StatusRequestEntityTooLarge, ErrorCodeTooLarge, message, err)
+}
+
+func NewAuthenticationError(ctx context.Context, message string, err error) ErrorResponse {
+	message = fmt.Sprintf("Authentication error: %s", message)
+	return newErrorResponse(ctx, http.StatusUnauthorized, ErrorCodeAuthentication, message, err)
 }
 
 func NewNotFoundError(ctx context.Context, message string, err error) ErrorResponse {
 	message = fmt.Sprintf("Not found: %s", message)
-	return newErrorResponse(ctx, http.StatusNotFound, message, err)
+	return newErrorResponse(ctx, http.StatusNotFound, ErrorCodeNotFound, message, err)
+}
+
+func NewConflictError(ctx context.Context, message string, err error) ErrorResponse {
+	message = fmt.Sprintf("Conflict: %s", message)
+	return newErrorResponse(ctx, http.StatusConflict, ErrorCodeConflict, message, err)
+}
+
+func NewConstraintError(ctx context.Context, message string, err error) ErrorResponse {
+	message = fmt.Sprintf("Constraint violation: %s", message)
+	return newErrorResponse(ctx, http.StatusUnprocessableEntity, ErrorCodeConstraint, message, err)
 }
 
+func NewUnavailableError(ctx context.Context, message string, err error) ErrorResponse {
+	message = fmt.Sprintf("Service unavailable: %s", message)
+	return newErrorResponse(ctx, http.StatusServiceUnavailable, ErrorCodeUnavailable, message, err)
+}
+
+func NewTimeoutError(ctx context.Context, message string, err error) ErrorResponse {
+	message = fmt.Sprintf("Timeout: %s", message)
+	return newErrorResponse(ctx, http.StatusGatewayTimeout, ErrorCodeTimeout, message, err)
+}
+
+// NewDAOError returns an error response of a failed database operation, classified
+// database errors (see dao.TranslateError) result in 409, 422, 503 or 504, others in 500.
 func NewDAOError(ctx context.Context, message string, err error) ErrorResponse {
+	switch {
+	case errors.Is(err, dao.ErrConflict):
+		return NewConflictError(ctx, message, err)
+	case errors.Is(err, dao.ErrConstraintViolation):
+		return NewConstraintError(ctx, message, err)
+	case errors.Is(err, dao.ErrUnavailable):
+		return NewUnavailableError(ctx, message, err)
+	case errors.Is(err, dao.ErrTimeout):
+		return NewTimeoutError(ctx, message, err)
+	}
 	message = fmt.Sprintf("DAO error: %s", message)
-	return newErrorResponse(ctx, http.StatusInternalServerError, message, err)
+	return newErrorResponse(ctx, http.StatusInternalServerError, ErrorCodeDAO, message, err)
 }
 
 func NewRenderError(ctx context.Context, message string, err error) ErrorResponse {
 	message = fmt.Sprintf("Rendering error: %s", message)
-	return newErrorResponse(ctx, http.StatusInternalServerError, message, err)
+	return newErrorResponse(ctx, http.StatusInternalServerError, ErrorCodeRender, message, err)
 }
@@ -0,0 +1,119 @@
+package payloads_test
+
+import (
+	"consoledot-go-template/internal/config"
+	"consoledot-go-template/internal/dao"
+	"consoledot-go-template/internal/logging"
+	"consoledot-go-template/internal/payloads"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/render"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errInternal = errors.New("pq: relation hellos does not exist")
+
+func renderError(t *testing.T, accept string, resp payloads.ErrorResponse) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/api/template/v1/hellos/1", nil)
+	req = req.WithContext(context.WithValue(req.Context(), render.ContentTypeCtxKey, render.ContentTypeJSON))
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rr := httptest.NewRecorder()
+	require.NoError(t, payloads.RenderError(rr, req, resp))
+	return rr
+}
+
+func TestRenderError(t *testing.T) {
+	ctx := logging.WithRequestID(context.Background(), "req-1")
+
+	t.Run("legacy format by default", func(t *testing.T) {
+		rr := renderError(t, "", payloads.NewNotFoundError(ctx, "hello", errors.New("no rows")))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Contains(t, rr.Header().Get("Content-Type"), "application/json")
+		var body payloads.ErrorResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+		assert.Equal(t, payloads.ErrorCodeNotFound, body.Code)
+		assert.Equal(t, "Not found: hello", body.Message)
+		assert.Equal(t, "no rows", body.Error)
+		assert.Equal(t, "req-1", body.RequestID)
+	})
+
+	t.Run("problem details when accepted", func(t *testing.T) {
+		rr := renderError(t, "application/json;q=0.9, application/problem+json",
+			payloads.NewNotFoundError(ctx, "hello", errors.New("no rows")))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Equal(t, payloads.ProblemContentType, rr.Header().Get("Content-Type"))
+		var body payloads.ProblemResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+		assert.Equal(t, payloads.ProblemResponse{
+			Type:      "urn:template-api:problem:not_found",
+			Title:     "Not found",
+			Status:    http.StatusNotFound,
+			Detail:    "Not found: hello: no rows",
+			Instance:  "/api/template/v1/hellos/1",
+			Code:      payloads.ErrorCodeNotFound,
+			RequestID: "req-1",
+		}, body)
+	})
+
+	t.Run("internal details are suppressed", func(t *testing.T) {
+		rr := renderError(t, payloads.ProblemContentType, payloads.NewDAOError(ctx, "hello", errInternal))
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		assert.NotContains(t, rr.Body.String(), errInternal.Error())
+	})
+
+	t.Run("database details of client errors are suppressed", func(t *testing.T) {
+		pgErr := &pgconn.PgError{
+			Severity:       "ERROR",
+			Code:           "23505",
+			Message:        `duplicate key value violates unique constraint "accounts_account_number_key"`,
+			ConstraintName: "accounts_account_number_key",
+		}
+		err := fmt.Errorf("pgx error: %w", dao.TranslateError(pgErr))
+		rr := renderError(t, "", payloads.NewDAOError(ctx, "hello", err))
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		assert.NotContains(t, rr.Body.String(), "SQLSTATE")
+		assert.NotContains(t, rr.Body.String(), "accounts_account_number_key")
+		var body payloads.ErrorResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+		assert.Equal(t, payloads.ErrorCodeConflict, body.Code)
+		assert.Empty(t, body.Error)
+	})
+
+	t.Run("internal details are exposed in development mode", func(t *testing.T) {
+		config.Application.Development = true
+		defer func() { config.Application.Development = false }()
+
+		rr := renderError(t, "", payloads.NewDAOError(ctx, "hello", errInternal))
+
+		assert.Contains(t, rr.Body.String(), errInternal.Error())
+	})
+
+	t.Run("database errors are classified", func(t *testing.T) {
+		tests := map[error]int{
+			dao.ErrConflict:            http.StatusConflict,
+			dao.ErrConstraintViolation: http.StatusUnprocessableEntity,
+			dao.ErrUnavailable:         http.StatusServiceUnavailable,
+			dao.ErrTimeout:             http.StatusGatewayTimeout,
+			errInternal:                http.StatusInternalServerError,
+		}
+		for kind, status := range tests {
+			err := &dao.Error{Kind: kind, Err: errInternal}
+			assert.Equal(t, status, payloads.NewDAOError(ctx, "hello", err).HTTPStatusCode, kind.Error())
+		}
+	})
+}
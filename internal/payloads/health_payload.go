@@ -0,0 +1,71 @@
+package payloads
+
+import (
+	"consoledot-go-template/internal/health"
+	"consoledot-go-template/internal/logging"
+	"context"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+const (
+	HealthStatusOK           = "ok"
+	HealthStatusFailing      = "failing"
+	HealthStatusShuttingDown = "shutting_down"
+)
+
+type HealthCheckResponse struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Detail     string `json:"detail,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+type HealthResponse struct {
+	HTTPStatusCode int                   `json:"-"`
+	Status         string                `json:"status"`
+	Checks         []HealthCheckResponse `json:"checks,omitempty"`
+}
+
+func (resp HealthResponse) Render(_ http.ResponseWriter, r *http.Request) error {
+	render.Status(r, resp.HTTPStatusCode)
+	return nil
+}
+
+func NewLivenessResponse() render.Renderer {
+	return HealthResponse{HTTPStatusCode: http.StatusOK, Status: HealthStatusOK}
+}
+
+// NewReadinessResponse creates a report of readiness checks, the service is ready
+// only when all checks pass and the service is not shutting down. The probe is not
+// authenticated, errors of failing checks are logged and not exposed in the report.
+func NewReadinessResponse(ctx context.Context, results []health.Result, shuttingDown bool) render.Renderer {
+	resp := HealthResponse{
+		HTTPStatusCode: http.StatusOK,
+		Status:         HealthStatusOK,
+		Checks:         make([]HealthCheckResponse, len(results)),
+	}
+
+	for i, result := range results {
+		check := HealthCheckResponse{
+			Name:       result.Name,
+			Status:     HealthStatusOK,
+			Detail:     result.Detail,
+			DurationMs: result.Duration.Milliseconds(),
+		}
+		if result.Err != nil {
+			logging.Logger(ctx).Warn().Err(result.Err).Str("check", result.Name).Msg("Readiness check failed")
+			check.Status = HealthStatusFailing
+			resp.HTTPStatusCode = http.StatusServiceUnavailable
+			resp.Status = HealthStatusFailing
+		}
+		resp.Checks[i] = check
+	}
+
+	if shuttingDown {
+		resp.HTTPStatusCode = http.StatusServiceUnavailable
+		resp.Status = HealthStatusShuttingDown
+	}
+	return resp
+}
@@ -2,6 +2,7 @@ package payloads
 
 import (
 	"consoledot-go-template/internal/models"
+	"consoledot-go-template/internal/validation"
 	"net/http"
 
 	"github.com/go-chi/render"
@@ -13,8 +14,12 @@ type HelloPayload struct {
 	Message string `json:"message"`
 }
 
+// HelloRequest validates the fields it shadows, the rules do not apply to responses,
+// since records stored before the validation was added may not satisfy them.
 type HelloRequest struct {
 	HelloPayload
+	Sender  string `json:"sender" validate:"required,max=254,email"`
+	Message string `json:"message" validate:"required,max=1024"`
 }
 
 type HelloResponse struct {
@@ -23,13 +28,13 @@ type HelloResponse struct {
 }
 
 // Bind is called by Chi to adjust the request payload data to your needs.
-// all basic binding is done by chi, so unless you need anything special, just return nil here.
-func (req HelloRequest) Bind(_ *http.Request) error {
+// all basic binding is done by chi, here the payload is validated against its validate struct tags.
+func (req *HelloRequest) Bind(_ *http.Request) error {
 	// ID is read-only field
 	// this is to showcase how you'd go about embedding the full model and protect only some of its fields
 	// this method has obvious pitfalls when you forget to add this protection.
 	req.ID = 0
-	return nil
+	return validation.Validate(req)
 }
 
 // Render is called by Chi to adjust the response payload data.
@@ -39,8 +44,13 @@ func (req HelloResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
 }
 
 func NewHelloResponse(hello *models.Hello) render.Renderer {
+	return newHelloResponse(hello)
+}
+
+func newHelloResponse(hello *models.Hello) HelloResponse {
 	return HelloResponse{
 		HelloPayload: HelloPayload{
+			ID:      uint64(hello.ID),
 			Sender:  hello.From,
 			Message: hello.Message,
 		},
@@ -48,10 +58,24 @@ func NewHelloResponse(hello *models.Hello) render.Renderer {
 	}
 }
 
-func NewHelloListResponse(hellos []*models.Hello) []render.Renderer {
-	list := make([]render.Renderer, len(hellos))
+type HelloListResponse struct {
+	Data  []HelloResponse `json:"data"`
+	Meta  ListMeta        `json:"meta"`
+	Links ListLinks       `json:"links"`
+}
+
+func (resp HelloListResponse) Render(_ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
+func NewHelloListResponse(hellos []*models.Hello, count int64, links ListLinks) render.Renderer {
+	list := make([]HelloResponse, len(hellos))
 	for i, hello := range hellos {
-		list[i] = NewHelloResponse(hello)
+		list[i] = newHelloResponse(hello)
+	}
+	return HelloListResponse{
+		Data:  list,
+		Meta:  ListMeta{Count: count},
+		Links: links,
 	}
-	return list
 }
@@ -0,0 +1,14 @@
+package payloads
+
+// ListMeta holds metadata of a paginated list
+type ListMeta struct {
+	// total number of items, not only the items on the page
+	Count int64 `json:"count"`
+}
+
+// ListLinks holds links to adjacent pages of a paginated list, a link is empty
+// when there is no such page.
+type ListLinks struct {
+	Next     string `json:"next,omitempty"`
+	Previous string `json:"previous,omitempty"`
+}
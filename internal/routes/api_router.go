@@ -2,11 +2,16 @@ package routes
 
 import (
 	"consoledot-go-template/api"
+	"consoledot-go-template/internal/apispec"
+	"consoledot-go-template/internal/config"
+	"consoledot-go-template/internal/identity"
 	"consoledot-go-template/internal/logging"
-	"consoledot-go-template/internal/services"
+	"consoledot-go-template/internal/payloads"
+	"consoledot-go-template/internal/telemetry"
 	"fmt"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
 	"github.com/rs/zerolog/log"
 )
 
@@ -22,18 +27,54 @@ func pathVersionedPrefix(version string) string {
 
 func apiRouter() *chi.Mux {
 	router := chi.NewRouter()
+	router.Use(logging.RequestIDMiddleware)
+	router.Use(telemetry.Middleware)
 	router.Use(logging.NewMiddleware(log.Logger))
+
+	// Set Content-Type to JSON for chi renderer. Warning: Non-chi routes
+	// MUST set Content-Type header on their own!
+	router.Use(render.SetContentType(render.ContentTypeJSON))
+	if config.Server.MaxBodyBytes > 0 {
+		router.Use(payloads.LimitBody(config.Server.MaxBodyBytes))
+	}
+
 	mountSpec(router)
-	mountAPI(router)
+
+	// all API routes require a valid identity of the caller
+	router.Group(func(r chi.Router) {
+		r.Use(identity.EnforceIdentity)
+		if config.OpenAPI.ValidateRequests || config.OpenAPI.ValidateResponses {
+			r.Use(specValidator().Middleware)
+		}
+		r.Use(identity.ResolveAccount)
+		mountAPI(r)
+	})
 	return router
 }
 
-func mountSpec(router *chi.Mux) {
+func specValidator() *apispec.Validator {
+	validator, err := apispec.NewValidator(config.OpenAPI.ValidateRequests, config.OpenAPI.ValidateResponses)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Unable to initialize OpenAPI validation")
+	}
+	return validator
+}
+
+func mountSpec(router chi.Router) {
 	router.Get("/openapi.json", api.ServeOpenAPISpec)
+	router.Get("/openapi.yaml", api.ServeOpenAPISpecYAML)
+	// both versioned prefixes share the router, the page loads the spec from the first one
+	docs := api.NewDocsHandler(pathVersionedPrefix("v1"))
+	router.Get("/docs", docs.ServeHTTP)
+	router.Get("/docs/", docs.ServeHTTP)
+	router.Get("/docs/{file}", api.ServeDocsAsset)
 }
 
-func mountAPI(router *chi.Mux) {
-	router.Route("/hellos", func(r chi.Router) {
-		r.Get("/", services.ListHellos)
-	})
+// APIRoutes returns all operations of the API, they are mounted in this order.
+func APIRoutes() []Route {
+	return helloRoutes()
+}
+
+func mountAPI(router chi.Router) {
+	mountRoutes(router, APIRoutes())
 }
@@ -0,0 +1,100 @@
+package routes
+
+import (
+	"consoledot-go-template/internal/dao"
+	"consoledot-go-template/internal/payloads"
+	"consoledot-go-template/internal/services"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func helloRoutes() []Route {
+	helloID := pathParam("id", "ID of the greeting", openapi3.NewInt64Schema())
+	helloID.Required = true
+
+	return []Route{
+		{
+			Method:      http.MethodGet,
+			Path:        "/hellos",
+			OperationID: "getGreetingList",
+			Description: "Returns a page of recorded greetings ordered by ID. Pages are selected either by offset " +
+				"or by a cursor, which is the ID of the last greeting of the previous page.",
+			Parameters:          helloListParams(),
+			ResponseDescription: "Success response",
+			Response:            &payloads.HelloListResponse{},
+			Errors:              withDAOErrors(http.StatusBadRequest, http.StatusUnauthorized),
+			Handler:             services.ListHellos,
+		},
+		{
+			Method:              http.MethodPost,
+			Path:                "/hellos",
+			OperationID:         "sayHi",
+			Description:         "Allows recording a greeting allowing to send a sender name and a custom greeting message.",
+			Request:             &payloads.HelloRequest{},
+			Status:              http.StatusCreated,
+			ResponseDescription: "The greeting was recorded",
+			Response:            &payloads.HelloResponse{},
+			Errors:              withDAOErrors(http.StatusBadRequest, http.StatusUnauthorized, http.StatusConflict, http.StatusUnprocessableEntity),
+			Handler:             services.SayHello,
+		},
+		{
+			Method:              http.MethodGet,
+			Path:                "/hellos/{id}",
+			OperationID:         "getGreeting",
+			Description:         "Returns a single greeting.",
+			Parameters:          []*openapi3.Parameter{helloID},
+			ResponseDescription: "Success response",
+			Response:            &payloads.HelloResponse{},
+			Errors:              withDAOErrors(http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound),
+			Handler:             services.GetHello,
+		},
+		{
+			Method:              http.MethodPut,
+			Path:                "/hellos/{id}",
+			OperationID:         "updateGreeting",
+			Description:         "Updates sender and message of a greeting.",
+			Parameters:          []*openapi3.Parameter{helloID},
+			Request:             &payloads.HelloRequest{},
+			ResponseDescription: "Success response",
+			Response:            &payloads.HelloResponse{},
+			Errors:              withDAOErrors(http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity),
+			Handler:             services.UpdateHello,
+		},
+		{
+			Method:              http.MethodDelete,
+			Path:                "/hellos/{id}",
+			OperationID:         "deleteGreeting",
+			Description:         "Deletes a greeting.",
+			Parameters:          []*openapi3.Parameter{helloID},
+			Status:              http.StatusNoContent,
+			ResponseDescription: "The greeting was deleted",
+			Errors:              withDAOErrors(http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound),
+			Handler:             services.DeleteHello,
+		},
+	}
+}
+
+func helloListParams() []*openapi3.Parameter {
+	sortPattern := fmt.Sprintf("^(%s)(:(asc|desc))?$", strings.Join(dao.HelloSortFields, "|"))
+
+	return []*openapi3.Parameter{
+		queryParam("limit", "Maximum number of greetings on the page",
+			openapi3.NewInt64Schema().WithMin(1).WithMax(services.MaxLimit).WithDefault(services.DefaultLimit)),
+		queryParam("offset", "Number of greetings to skip, cannot be combined with cursor",
+			openapi3.NewInt64Schema().WithMin(0).WithDefault(0)),
+		queryParam("cursor", "ID of the last greeting of the previous page (keyset pagination)",
+			openapi3.NewInt64Schema().WithMin(0)),
+		queryParam("sender", "Only greetings with sender containing the value (case-insensitive)",
+			openapi3.NewStringSchema()),
+		queryParam("recipient", "Only greetings with recipient containing the value (case-insensitive)",
+			openapi3.NewStringSchema()),
+		queryParam("message", "Only greetings with message containing the value (case-insensitive)",
+			openapi3.NewStringSchema()),
+		queryParam("sort_by", "Sort order in the form field:asc or field:desc, the direction defaults to asc. "+
+			"Cursor can only be combined with sorting by id.",
+			openapi3.NewStringSchema().WithPattern(sortPattern).WithDefault("id:asc")),
+	}
+}
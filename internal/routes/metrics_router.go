@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"consoledot-go-template/internal/config"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// MetricsRouter serves Prometheus metrics, it is meant to be served on a separate
+// port which is not exposed through the public gateway.
+func MetricsRouter() *chi.Mux {
+	router := chi.NewRouter()
+	router.Handle(config.Prometheus.Path, promhttp.Handler())
+	return router
+}
@@ -1,6 +1,8 @@
 package routes
 
 import (
+	"consoledot-go-template/internal/services"
+
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 )
@@ -8,11 +10,14 @@ import (
 func RootRouter() *chi.Mux {
 	router := chi.NewRouter()
 
-	apiR := apiRouter()
+	// Kubernetes probes, these are not exposed through the public gateway
+	router.Group(func(r chi.Router) {
+		r.Use(render.SetContentType(render.ContentTypeJSON))
+		r.Get("/healthz", services.Liveness)
+		r.Get("/readyz", services.Readiness)
+	})
 
-	// Set Content-Type to JSON for chi renderer. Warning: Non-chi routes
-	// MUST set Content-Type header on their own!
-	apiR.Use(render.SetContentType(render.ContentTypeJSON))
+	apiR := apiRouter()
 
 	router.Mount(pathVersionedPrefix("v1"), apiR)
 	router.Mount(pathVersionedPrefix("v1.0"), apiR)
@@ -0,0 +1,73 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+)
+
+// Route describes a single API operation. The same description is used to mount
+// the handler and to generate paths of the OpenAPI spec (see cmd/openapi_spec),
+// so the spec cannot drift from the router.
+type Route struct {
+	// HTTP method, e.g. http.MethodGet
+	Method string
+	// chi pattern relative to the API prefix, e.g. /hellos/{id}
+	Path string
+	// unique operation ID in the spec
+	OperationID string
+	// description of the operation in the spec
+	Description string
+	// path and query parameters, every path variable must be described
+	Parameters []*openapi3.Parameter
+	// request payload, nil when the operation has no request body
+	Request interface{}
+	// successful HTTP status code, defaults to 200
+	Status int
+	// description of the successful response
+	ResponseDescription string
+	// response payload, nil when the successful response has no content
+	Response interface{}
+	// HTTP status codes of error responses, the payload is payloads.ErrorResponse
+	Errors []int
+	// handler of the operation
+	Handler http.HandlerFunc
+}
+
+// SuccessStatus returns HTTP status code of the successful response.
+func (rt Route) SuccessStatus() int {
+	if rt.Status == 0 {
+		return http.StatusOK
+	}
+	return rt.Status
+}
+
+// ErrorStatuses returns HTTP status codes of error responses. Operations with a request
+// body can also fail with 413, bodies are limited for all routes (see payloads.LimitBody).
+func (rt Route) ErrorStatuses() []int {
+	if rt.Request == nil {
+		return rt.Errors
+	}
+	return append([]int{http.StatusRequestEntityTooLarge}, rt.Errors...)
+}
+
+func mountRoutes(router chi.Router, routes []Route) {
+	for _, rt := range routes {
+		router.Method(rt.Method, rt.Path, rt.Handler)
+	}
+}
+
+func pathParam(name, description string, schema *openapi3.Schema) *openapi3.Parameter {
+	return openapi3.NewPathParameter(name).WithDescription(description).WithSchema(schema)
+}
+
+func queryParam(name, description string, schema *openapi3.Schema) *openapi3.Parameter {
+	return openapi3.NewQueryParameter(name).WithDescription(description).WithSchema(schema)
+}
+
+// withDAOErrors returns the error statuses along with statuses of database failures,
+// which can be returned by all operations accessing the database.
+func withDAOErrors(statuses ...int) []int {
+	return append(statuses, http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusGatewayTimeout)
+}
@@ -0,0 +1,57 @@
+package routes
+
+import (
+	"consoledot-go-template/api"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var pathParamRegexp = regexp.MustCompile(`{([^:}]+)`)
+
+func TestAPIRoutes(t *testing.T) {
+	t.Run("path parameters are described", func(t *testing.T) {
+		for _, rt := range APIRoutes() {
+			described := make(map[string]bool)
+			for _, param := range rt.Parameters {
+				if param.In == "path" {
+					described[param.Name] = true
+				}
+			}
+			for _, match := range pathParamRegexp.FindAllStringSubmatch(rt.Path, -1) {
+				assert.True(t, described[match[1]], "parameter %s of %s is not described", match[1], rt.OperationID)
+			}
+		}
+	})
+
+	t.Run("all mounted routes are described", func(t *testing.T) {
+		// the spec and its documentation are not part of the spec
+		described := map[string]bool{"GET /openapi.json": true, "GET /openapi.yaml": true, "GET /docs": true, "GET /docs/": true, "GET /docs/{file}": true}
+		for _, rt := range APIRoutes() {
+			described[rt.Method+" "+rt.Path] = true
+		}
+
+		err := chi.Walk(apiRouter(), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+			assert.True(t, described[method+" "+route], "route %s %s is not described", method, route)
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("embedded spec is up to date", func(t *testing.T) {
+		spec, err := api.Spec()
+		require.NoError(t, err)
+
+		for _, rt := range APIRoutes() {
+			pathItem := spec.Paths.Find(rt.Path)
+			require.NotNil(t, pathItem, "path %s is missing, run make generate-openapi", rt.Path)
+			operation := pathItem.GetOperation(rt.Method)
+			require.NotNil(t, operation, "operation %s is missing, run make generate-openapi", rt.OperationID)
+			assert.Equal(t, rt.OperationID, operation.OperationID)
+		}
+	})
+}
@@ -7,8 +7,6 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-
-	"github.com/go-chi/render"
 )
 
 // writeBasicError returns an error code without utilizing the Chi rendering stack. It can
@@ -20,16 +18,12 @@ func writeBasicError(w http.ResponseWriter, r *http.Request, err error) {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusInternalServerError)
 
-	wrappedMessage := ""
-	if errors.Unwrap(err) != nil {
-		wrappedMessage = errors.Unwrap(err).Error()
-	}
-	// write generic error body by string
-	_, _ = w.Write([]byte(fmt.Sprintf(`{"msg": "%s", "error": "%s"}`, err.Error(), wrappedMessage)))
+	// the error is internal, it is logged and not written to the client
+	_, _ = w.Write([]byte(fmt.Sprintf(`{"code": "%s", "msg": "Rendering error: unable to render error"}`, payloads.ErrorCodeRender)))
 }
 
-func renderError(w http.ResponseWriter, r *http.Request, renderer render.Renderer) {
-	if errRender := render.Render(w, r, renderer); errRender != nil {
+func renderError(w http.ResponseWriter, r *http.Request, resp payloads.ErrorResponse) {
+	if errRender := payloads.RenderError(w, r, resp); errRender != nil {
 		writeBasicError(w, r, errRender)
 	}
 }
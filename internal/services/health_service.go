@@ -0,0 +1,30 @@
+package services
+
+import (
+	"consoledot-go-template/internal/health"
+	"consoledot-go-template/internal/payloads"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// Liveness responds as long as the process is able to serve HTTP requests
+func Liveness(w http.ResponseWriter, r *http.Request) {
+	if err := render.Render(w, r, payloads.NewLivenessResponse()); err != nil {
+		writeBasicError(w, r, err)
+	}
+}
+
+// Readiness reports status of all registered dependency checks
+func Readiness(w http.ResponseWriter, r *http.Request) {
+	// fail fast to let the traffic drain, dependencies are not interesting anymore
+	var results []health.Result
+	shuttingDown := health.ShuttingDown()
+	if !shuttingDown {
+		results = health.RunChecks(r.Context())
+	}
+
+	if err := render.Render(w, r, payloads.NewReadinessResponse(r.Context(), results, shuttingDown)); err != nil {
+		writeBasicError(w, r, err)
+	}
+}
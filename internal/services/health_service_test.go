@@ -0,0 +1,59 @@
+package services_test
+
+import (
+	"consoledot-go-template/internal/health"
+	"consoledot-go-template/internal/payloads"
+	"consoledot-go-template/internal/services"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadiness(t *testing.T) {
+	readiness := func(t *testing.T) (int, payloads.HealthResponse, string) {
+		t.Helper()
+		req, err := http.NewRequestWithContext(context.Background(), "GET", "/readyz", nil)
+		require.NoError(t, err, "failed to create request")
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(services.Readiness)
+		handler.ServeHTTP(rr, req)
+
+		var report payloads.HealthResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &report))
+		return rr.Code, report, rr.Body.String()
+	}
+
+	t.Cleanup(func() { health.Unregister("test") })
+
+	t.Run("reports failing check", func(t *testing.T) {
+		health.Register("test", func(ctx context.Context) (string, error) {
+			return "", errors.New("dependency is down")
+		})
+
+		code, report, body := readiness(t)
+		require.Equal(t, http.StatusServiceUnavailable, code, "Wrong status code")
+		assert.Equal(t, payloads.HealthStatusFailing, report.Status)
+		require.Equal(t, 1, len(report.Checks))
+		assert.Equal(t, payloads.HealthStatusFailing, report.Checks[0].Status)
+		assert.NotContains(t, body, "dependency is down", "Check error must not be exposed")
+	})
+
+	t.Run("reports passing check with detail", func(t *testing.T) {
+		health.Register("test", func(ctx context.Context) (string, error) {
+			return "version 1", nil
+		})
+
+		code, report, _ := readiness(t)
+		require.Equal(t, http.StatusOK, code, "Wrong status code")
+		assert.Equal(t, payloads.HealthStatusOK, report.Status)
+		require.Equal(t, 1, len(report.Checks))
+		assert.Equal(t, "version 1", report.Checks[0].Detail)
+	})
+}
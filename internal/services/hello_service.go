@@ -4,8 +4,12 @@ import (
 	"consoledot-go-template/internal/dao"
 	"consoledot-go-template/internal/models"
 	"consoledot-go-template/internal/payloads"
+	"consoledot-go-template/internal/telemetry"
+	"fmt"
 	"net/http"
+	"strconv"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 )
 
@@ -13,35 +17,173 @@ import (
 const Recipient = "Ondrej Ezr<oezr@redhat.com"
 
 func ListHellos(w http.ResponseWriter, r *http.Request) {
-	helloDao := dao.GetHelloDao(r.Context())
-	hellos, err := helloDao.List(r.Context(), 100, 0)
+	ctx, span := telemetry.StartSpan(r.Context(), "ListHellos")
+	defer span.End()
+
+	page, err := parsePagination(r)
 	if err != nil {
-		renderError(w, r, payloads.NewDAOError(r.Context(), "list hellos", err))
+		renderError(w, r, payloads.NewInvalidRequestError(ctx, "list hellos", err))
 		return
 	}
+	params, err := parseHelloListParams(r)
+	if err != nil {
+		renderError(w, r, payloads.NewInvalidRequestError(ctx, "list hellos", err))
+		return
+	}
+	if page.CursorMode && params.SortBy != "" && params.SortBy != "id" {
+		err = fmt.Errorf("%w: cursor can only be combined with sorting by id", ErrInvalidPagination)
+		renderError(w, r, payloads.NewInvalidRequestError(ctx, "list hellos", err))
+		return
+	}
+
+	// one more item is fetched to find out whether there is a next page
+	helloDao := dao.GetHelloDao(ctx)
+	var hellos []*models.Hello
+	if page.CursorMode {
+		hellos, err = helloDao.ListAfter(ctx, params, page.Cursor, page.Limit+1)
+	} else {
+		hellos, err = helloDao.List(ctx, params, page.Limit+1, page.Offset)
+	}
+	if err != nil {
+		renderError(w, r, payloads.NewDAOError(ctx, "list hellos", err))
+		return
+	}
+
+	count, err := helloDao.Count(ctx, params)
+	if err != nil {
+		renderError(w, r, payloads.NewDAOError(ctx, "count hellos", err))
+		return
+	}
+
+	hasNext := int64(len(hellos)) > page.Limit
+	if hasNext {
+		hellos = hellos[:page.Limit]
+	}
+	var lastID int64
+	if len(hellos) > 0 {
+		lastID = hellos[len(hellos)-1].ID
+	}
 
-	if renderErr := render.RenderList(w, r, payloads.NewHelloListResponse(hellos)); renderErr != nil {
-		renderError(w, r, payloads.NewRenderError(r.Context(), "unable to render hello list", renderErr))
+	links := page.links(r, lastID, hasNext)
+	if renderErr := render.Render(w, r, payloads.NewHelloListResponse(hellos, count, links)); renderErr != nil {
+		renderError(w, r, payloads.NewRenderError(ctx, "unable to render hello list", renderErr))
 	}
 }
 
 func SayHello(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.StartSpan(r.Context(), "SayHello")
+	defer span.End()
+
 	payload := payloads.HelloRequest{}
 	if err := render.Bind(r, &payload); err != nil {
-		renderError(w, r, payloads.NewInvalidRequestError(r.Context(), "say hello", err))
+		renderError(w, r, payloads.NewInvalidRequestError(ctx, "say hello", err))
 		return
 	}
 
 	hello := models.Hello{To: Recipient, From: payload.Sender, Message: payload.Message}
 
-	helloDao := dao.GetHelloDao(r.Context())
-	if err := helloDao.Record(r.Context(), &hello); err != nil {
-		renderError(w, r, payloads.NewDAOError(r.Context(), "record hello", err))
+	helloDao := dao.GetHelloDao(ctx)
+	if err := helloDao.Record(ctx, &hello); err != nil {
+		renderError(w, r, payloads.NewDAOError(ctx, "record hello", err))
 		return
 	}
 
 	render.Status(r, http.StatusCreated)
 	if rndrErr := render.Render(w, r, payloads.NewHelloResponse(&hello)); rndrErr != nil {
-		renderError(w, r, payloads.NewRenderError(r.Context(), "unable to render hello", rndrErr))
+		renderError(w, r, payloads.NewRenderError(ctx, "unable to render hello", rndrErr))
+	}
+}
+
+func GetHello(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.StartSpan(r.Context(), "GetHello")
+	defer span.End()
+
+	id, err := helloID(r)
+	if err != nil {
+		renderError(w, r, payloads.NewInvalidRequestError(ctx, "hello id", err))
+		return
+	}
+
+	helloDao := dao.GetHelloDao(ctx)
+	hello, err := helloDao.GetByID(ctx, id)
+	if err != nil {
+		renderNotFoundOrDAOError(w, r, err, "get hello")
+		return
+	}
+
+	if rndrErr := render.Render(w, r, payloads.NewHelloResponse(hello)); rndrErr != nil {
+		renderError(w, r, payloads.NewRenderError(ctx, "unable to render hello", rndrErr))
+	}
+}
+
+func UpdateHello(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.StartSpan(r.Context(), "UpdateHello")
+	defer span.End()
+
+	id, err := helloID(r)
+	if err != nil {
+		renderError(w, r, payloads.NewInvalidRequestError(ctx, "hello id", err))
+		return
+	}
+
+	payload := payloads.HelloRequest{}
+	if err = render.Bind(r, &payload); err != nil {
+		renderError(w, r, payloads.NewInvalidRequestError(ctx, "update hello", err))
+		return
+	}
+
+	hello := models.Hello{ID: id, From: payload.Sender, Message: payload.Message}
+
+	helloDao := dao.GetHelloDao(ctx)
+	if err = helloDao.Update(ctx, &hello); err != nil {
+		renderNotFoundOrDAOError(w, r, err, "update hello")
+		return
+	}
+
+	if rndrErr := render.Render(w, r, payloads.NewHelloResponse(&hello)); rndrErr != nil {
+		renderError(w, r, payloads.NewRenderError(ctx, "unable to render hello", rndrErr))
+	}
+}
+
+func DeleteHello(w http.ResponseWriter, r *http.Request) {
+	ctx, span := telemetry.StartSpan(r.Context(), "DeleteHello")
+	defer span.End()
+
+	id, err := helloID(r)
+	if err != nil {
+		renderError(w, r, payloads.NewInvalidRequestError(ctx, "hello id", err))
+		return
+	}
+
+	helloDao := dao.GetHelloDao(ctx)
+	if err = helloDao.Delete(ctx, id); err != nil {
+		renderNotFoundOrDAOError(w, r, err, "delete hello")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func parseHelloListParams(r *http.Request) (*dao.HelloListParams, error) {
+	query := r.URL.Query()
+	params := &dao.HelloListParams{
+		Sender:    query.Get("sender"),
+		Recipient: query.Get("recipient"),
+		Message:   query.Get("message"),
+	}
+
+	var err error
+	params.SortBy, params.SortDesc, err = parseSort(r, dao.HelloSortFields)
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+func helloID(r *http.Request) (int64, error) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse id: %w", err)
 	}
+	return id, nil
 }
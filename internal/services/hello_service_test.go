@@ -4,20 +4,50 @@ import (
 	"bytes"
 	"consoledot-go-template/internal/dao"
 	"consoledot-go-template/internal/dao/stub"
+	"consoledot-go-template/internal/identity"
+	"consoledot-go-template/internal/models"
+	"consoledot-go-template/internal/payloads"
 	"consoledot-go-template/internal/services"
 	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// withAccount returns context of a tenant as it is resolved by identity middleware
+func withAccount(ctx context.Context, id int64) context.Context {
+	return identity.WithAccount(ctx, &models.Account{ID: id, OrgID: "org"})
+}
+
+func sayHello(t *testing.T, ctx context.Context, sender string) {
+	t.Helper()
+	values := map[string]interface{}{
+		"message": "hello beautiful Open Source world!",
+		"sender":  sender,
+	}
+	jsonData, err := json.Marshal(values)
+	require.NoError(t, err, "unable to marshal input payload to JSON")
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "/api/template/hellos", bytes.NewBuffer(jsonData))
+	require.NoError(t, err, "failed to create request")
+	req.Header.Add("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(services.SayHello)
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusCreated, rr.Code, "Wrong status code")
+}
+
 func TestListHellos(t *testing.T) {
 	t.Run("handles empty database well", func(t *testing.T) {
-		ctx := stub.WithHelloDao(context.Background())
+		ctx := withAccount(stub.WithHelloDao(context.Background()), 1)
 
 		req, err := http.NewRequestWithContext(ctx, "GET", "/api/template/hellos", nil)
 		require.NoError(t, err, "failed to create request")
@@ -27,37 +57,301 @@ func TestListHellos(t *testing.T) {
 		handler.ServeHTTP(rr, req)
 
 		require.Equal(t, http.StatusOK, rr.Code, "Wrong status code")
-		assert.Equal(t, "[]\n", rr.Body.String())
+		assert.JSONEq(t, `{"data":[],"meta":{"count":0},"links":{}}`, rr.Body.String())
 	})
-}
 
-func TestSayHello(t *testing.T) {
-	t.Run("records hello with a static recipient", func(t *testing.T) {
+	t.Run("does not list hellos of other accounts", func(t *testing.T) {
+		daoCtx := stub.WithHelloDao(context.Background())
+		sayHello(t, withAccount(daoCtx, 1), "first@example.com")
+		sayHello(t, withAccount(daoCtx, 2), "second@example.com")
+
+		req, err := http.NewRequestWithContext(withAccount(daoCtx, 2), "GET", "/api/template/hellos", nil)
+		require.NoError(t, err, "failed to create request")
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(services.ListHellos)
+		handler.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code, "Wrong status code")
+		var hellos payloads.HelloListResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &hellos))
+		require.Equal(t, 1, len(hellos.Data))
+		assert.Equal(t, "second@example.com", hellos.Data[0].Sender)
+		assert.Equal(t, int64(1), hellos.Meta.Count)
+	})
+
+	t.Run("fails without an account", func(t *testing.T) {
 		ctx := stub.WithHelloDao(context.Background())
-		hDao := dao.GetHelloDao(ctx)
 
-		values := map[string]interface{}{
-			"message": "hello beautiful Open Source world!",
-			"sender":  "test@example.com",
+		req, err := http.NewRequestWithContext(ctx, "GET", "/api/template/hellos", nil)
+		require.NoError(t, err, "failed to create request")
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(services.ListHellos)
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code, "Wrong status code")
+	})
+}
+
+func TestListHellosPagination(t *testing.T) {
+	listHellos := func(t *testing.T, ctx context.Context, query string) (int, payloads.HelloListResponse) {
+		t.Helper()
+		req, err := http.NewRequestWithContext(ctx, "GET", "/api/template/hellos?"+query, nil)
+		require.NoError(t, err, "failed to create request")
+
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(services.ListHellos)
+		handler.ServeHTTP(rr, req)
+
+		var result payloads.HelloListResponse
+		if rr.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &result))
 		}
-		jsonData, err := json.Marshal(values)
-		require.NoError(t, err, "unable to marshal input payload to JSON")
+		return rr.Code, result
+	}
+
+	ctx := withAccount(stub.WithHelloDao(context.Background()), 1)
+	for i := 0; i < 5; i++ {
+		sayHello(t, ctx, "test@example.com")
+	}
+
+	t.Run("paginates by offset", func(t *testing.T) {
+		code, result := listHellos(t, ctx, "limit=2&offset=2")
+		require.Equal(t, http.StatusOK, code, "Wrong status code")
+
+		require.Equal(t, 2, len(result.Data))
+		assert.Equal(t, uint64(3), result.Data[0].ID)
+		assert.Equal(t, int64(5), result.Meta.Count)
+		assert.Equal(t, "/api/template/hellos?limit=2&offset=4", result.Links.Next)
+		assert.Equal(t, "/api/template/hellos?limit=2&offset=0", result.Links.Previous)
+	})
+
+	t.Run("has no next link on the last page", func(t *testing.T) {
+		code, result := listHellos(t, ctx, "limit=2&offset=4")
+		require.Equal(t, http.StatusOK, code, "Wrong status code")
+
+		require.Equal(t, 1, len(result.Data))
+		assert.Empty(t, result.Links.Next)
+	})
 
-		req, err := http.NewRequestWithContext(ctx, "POST", "/api/template/hellos", bytes.NewBuffer(jsonData))
+	t.Run("paginates by cursor", func(t *testing.T) {
+		code, result := listHellos(t, ctx, "limit=3&cursor=1")
+		require.Equal(t, http.StatusOK, code, "Wrong status code")
+
+		require.Equal(t, 3, len(result.Data))
+		assert.Equal(t, uint64(2), result.Data[0].ID)
+		assert.Equal(t, "/api/template/hellos?cursor=4&limit=3", result.Links.Next)
+		assert.Empty(t, result.Links.Previous)
+	})
+
+	t.Run("rejects too large limit", func(t *testing.T) {
+		code, _ := listHellos(t, ctx, "limit=100000")
+		assert.Equal(t, http.StatusBadRequest, code, "Wrong status code")
+	})
+
+	t.Run("rejects offset combined with cursor", func(t *testing.T) {
+		code, _ := listHellos(t, ctx, "offset=1&cursor=1")
+		assert.Equal(t, http.StatusBadRequest, code, "Wrong status code")
+	})
+}
+
+func TestListHellosFilter(t *testing.T) {
+	listHellos := func(t *testing.T, ctx context.Context, query string) (int, []string) {
+		t.Helper()
+		req, err := http.NewRequestWithContext(ctx, "GET", "/api/template/hellos?"+query, nil)
 		require.NoError(t, err, "failed to create request")
-		req.Header.Add("Content-Type", "application/json")
 
 		rr := httptest.NewRecorder()
-		handler := http.HandlerFunc(services.SayHello)
+		handler := http.HandlerFunc(services.ListHellos)
 		handler.ServeHTTP(rr, req)
 
-		require.Equal(t, http.StatusCreated, rr.Code, "Wrong status code")
+		var result payloads.HelloListResponse
+		senders := make([]string, 0)
+		if rr.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &result))
+			for _, hello := range result.Data {
+				senders = append(senders, hello.Sender)
+			}
+		}
+		return rr.Code, senders
+	}
+
+	ctx := withAccount(stub.WithHelloDao(context.Background()), 1)
+	sayHello(t, ctx, "bob@example.com")
+	sayHello(t, ctx, "alice@example.com")
+	sayHello(t, ctx, "Carol@redhat.com")
 
-		hellos, listErr := hDao.List(ctx, 100, 0)
+	t.Run("filters by sender substring ignoring case", func(t *testing.T) {
+		code, senders := listHellos(t, ctx, "sender=CAROL")
+		require.Equal(t, http.StatusOK, code, "Wrong status code")
+		assert.Equal(t, []string{"Carol@redhat.com"}, senders)
+	})
+
+	t.Run("sorts by sender descending", func(t *testing.T) {
+		code, senders := listHellos(t, ctx, "sender=example&sort_by=sender:desc")
+		require.Equal(t, http.StatusOK, code, "Wrong status code")
+		assert.Equal(t, []string{"bob@example.com", "alice@example.com"}, senders)
+	})
+
+	t.Run("rejects sort field out of the allow-list", func(t *testing.T) {
+		code, _ := listHellos(t, ctx, "sort_by=account_id")
+		assert.Equal(t, http.StatusBadRequest, code, "Wrong status code")
+	})
+
+	t.Run("rejects uppercase sort direction", func(t *testing.T) {
+		code, _ := listHellos(t, ctx, "sort_by=sender:DESC")
+		assert.Equal(t, http.StatusBadRequest, code, "Wrong status code")
+	})
+
+	t.Run("reports sort errors as invalid sort", func(t *testing.T) {
+		req, err := http.NewRequestWithContext(ctx, "GET", "/api/template/hellos?sort_by=account_id", nil)
+		require.NoError(t, err, "failed to create request")
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(services.ListHellos).ServeHTTP(rr, req)
+
+		var body payloads.ErrorResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+		assert.Contains(t, body.Error, services.ErrInvalidSort.Error())
+		assert.NotContains(t, body.Error, services.ErrInvalidPagination.Error())
+	})
+
+	t.Run("rejects cursor with sorting by other field than id", func(t *testing.T) {
+		code, _ := listHellos(t, ctx, "cursor=1&sort_by=sender")
+		assert.Equal(t, http.StatusBadRequest, code, "Wrong status code")
+	})
+}
+
+func TestSayHello(t *testing.T) {
+	t.Run("rejects invalid payload listing all fields", func(t *testing.T) {
+		ctx := withAccount(stub.WithHelloDao(context.Background()), 1)
+
+		body := map[string]interface{}{"sender": "not an email", "message": strings.Repeat("x", 1025)}
+		rr := serveHello(t, ctx, "POST", body, services.SayHello)
+		require.Equal(t, http.StatusBadRequest, rr.Code, "Wrong status code")
+
+		var errResp payloads.ErrorResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &errResp))
+		require.Equal(t, 2, len(errResp.Fields))
+		assert.Equal(t, "sender", errResp.Fields[0].Field)
+		assert.Equal(t, "message", errResp.Fields[1].Field)
+
+		hellos, err := dao.GetHelloDao(ctx).List(ctx, nil, 100, 0)
+		require.NoError(t, err)
+		assert.Empty(t, hellos)
+	})
+
+	t.Run("records hello with a static recipient", func(t *testing.T) {
+		ctx := withAccount(stub.WithHelloDao(context.Background()), 1)
+		hDao := dao.GetHelloDao(ctx)
+
+		sayHello(t, ctx, "test@example.com")
+
+		hellos, listErr := hDao.List(ctx, nil, 100, 0)
 		require.NoError(t, listErr, "failed to list hellos")
 
 		assert.Equal(t, 1, len(hellos))
 		assert.Equal(t, "test@example.com", hellos[0].From)
 		assert.Equal(t, services.Recipient, hellos[0].To)
+		assert.Equal(t, int64(1), hellos[0].AccountID)
+	})
+}
+
+// withID adds id URL parameter as it is routed by chi
+func withID(ctx context.Context, id string) context.Context {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+	return context.WithValue(ctx, chi.RouteCtxKey, rctx)
+}
+
+func serveHello(t *testing.T, ctx context.Context, method string, body interface{}, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+	var buffer bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&buffer).Encode(body), "unable to marshal input payload to JSON")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, "/api/template/hellos/1", &buffer)
+	require.NoError(t, err, "failed to create request")
+	req.Header.Add("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestGetHello(t *testing.T) {
+	t.Run("returns hello of the account", func(t *testing.T) {
+		ctx := withAccount(stub.WithHelloDao(context.Background()), 1)
+		sayHello(t, ctx, "test@example.com")
+
+		rr := serveHello(t, withID(ctx, "1"), "GET", nil, services.GetHello)
+		require.Equal(t, http.StatusOK, rr.Code, "Wrong status code")
+
+		var hello map[string]interface{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &hello))
+		assert.Equal(t, float64(1), hello["id"])
+		assert.Equal(t, "test@example.com", hello["sender"])
+	})
+
+	t.Run("returns not found for hello of another account", func(t *testing.T) {
+		daoCtx := stub.WithHelloDao(context.Background())
+		sayHello(t, withAccount(daoCtx, 1), "test@example.com")
+
+		rr := serveHello(t, withID(withAccount(daoCtx, 2), "1"), "GET", nil, services.GetHello)
+		assert.Equal(t, http.StatusNotFound, rr.Code, "Wrong status code")
+	})
+
+	t.Run("rejects invalid id", func(t *testing.T) {
+		ctx := withAccount(stub.WithHelloDao(context.Background()), 1)
+
+		rr := serveHello(t, withID(ctx, "abc"), "GET", nil, services.GetHello)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, "Wrong status code")
+	})
+}
+
+func TestUpdateHello(t *testing.T) {
+	t.Run("updates sender and message", func(t *testing.T) {
+		ctx := withAccount(stub.WithHelloDao(context.Background()), 1)
+		sayHello(t, ctx, "test@example.com")
+
+		body := map[string]interface{}{"sender": "updated@example.com", "message": "updated"}
+		rr := serveHello(t, withID(ctx, "1"), "PUT", body, services.UpdateHello)
+		require.Equal(t, http.StatusOK, rr.Code, "Wrong status code")
+
+		hello, err := dao.GetHelloDao(ctx).GetByID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "updated@example.com", hello.From)
+		assert.Equal(t, "updated", hello.Message)
+		assert.Equal(t, services.Recipient, hello.To)
+	})
+
+	t.Run("returns not found for missing hello", func(t *testing.T) {
+		ctx := withAccount(stub.WithHelloDao(context.Background()), 1)
+
+		body := map[string]interface{}{"sender": "updated@example.com", "message": "updated"}
+		rr := serveHello(t, withID(ctx, "1"), "PUT", body, services.UpdateHello)
+		assert.Equal(t, http.StatusNotFound, rr.Code, "Wrong status code")
+	})
+}
+
+func TestDeleteHello(t *testing.T) {
+	t.Run("deletes hello", func(t *testing.T) {
+		ctx := withAccount(stub.WithHelloDao(context.Background()), 1)
+		sayHello(t, ctx, "test@example.com")
+
+		rr := serveHello(t, withID(ctx, "1"), "DELETE", nil, services.DeleteHello)
+		require.Equal(t, http.StatusNoContent, rr.Code, "Wrong status code")
+
+		_, err := dao.GetHelloDao(ctx).GetByID(ctx, 1)
+		assert.ErrorIs(t, err, dao.ErrNoRows)
+	})
+
+	t.Run("returns not found for hello of another account", func(t *testing.T) {
+		daoCtx := stub.WithHelloDao(context.Background())
+		sayHello(t, withAccount(daoCtx, 1), "test@example.com")
+
+		rr := serveHello(t, withID(withAccount(daoCtx, 2), "1"), "DELETE", nil, services.DeleteHello)
+		assert.Equal(t, http.StatusNotFound, rr.Code, "Wrong status code")
 	})
 }
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"consoledot-go-template/internal/payloads"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultLimit is the page size used when the client does not ask for any
+	DefaultLimit = 100
+	// MaxLimit is the largest page size a client can ask for
+	MaxLimit = 1000
+)
+
+var (
+	ErrInvalidPagination = errors.New("invalid pagination")
+	ErrInvalidSort       = errors.New("invalid sort")
+)
+
+// pagination holds parsed list query parameters. Lists are paginated either by offset
+// or by a cursor, which is the ID of the last item of the previous page (keyset pagination).
+type pagination struct {
+	Limit  int64
+	Offset int64
+	Cursor int64
+	// true when the cursor parameter was provided
+	CursorMode bool
+}
+
+func parsePagination(r *http.Request) (pagination, error) {
+	var err error
+	query := r.URL.Query()
+	result := pagination{Limit: DefaultLimit}
+
+	if value := query.Get("limit"); value != "" {
+		result.Limit, err = strconv.ParseInt(value, 10, 64)
+		if err != nil || result.Limit < 1 || result.Limit > MaxLimit {
+			return result, fmt.Errorf("%w: limit must be a number between 1 and %d", ErrInvalidPagination, MaxLimit)
+		}
+	}
+
+	if value := query.Get("offset"); value != "" {
+		result.Offset, err = strconv.ParseInt(value, 10, 64)
+		if err != nil || result.Offset < 0 {
+			return result, fmt.Errorf("%w: offset must be a non-negative number", ErrInvalidPagination)
+		}
+	}
+
+	if value := query.Get("cursor"); value != "" {
+		if query.Has("offset") {
+			return result, fmt.Errorf("%w: offset and cursor cannot be combined", ErrInvalidPagination)
+		}
+		result.Cursor, err = strconv.ParseInt(value, 10, 64)
+		if err != nil || result.Cursor < 0 {
+			return result, fmt.Errorf("%w: cursor must be a non-negative number", ErrInvalidPagination)
+		}
+		result.CursorMode = true
+	}
+
+	return result, nil
+}
+
+// links returns links to adjacent pages. In cursor mode, only the next page link is
+// provided, lastID is the ID of the last item on the page and hasNext tells whether
+// there are more items after it.
+func (p pagination) links(r *http.Request, lastID int64, hasNext bool) payloads.ListLinks {
+	result := payloads.ListLinks{}
+	pageURL := func(set map[string]int64) string {
+		query := r.URL.Query()
+		query.Del("offset")
+		query.Del("cursor")
+		query.Set("limit", strconv.FormatInt(p.Limit, 10))
+		for key, value := range set {
+			query.Set(key, strconv.FormatInt(value, 10))
+		}
+		return fmt.Sprintf("%s?%s", r.URL.Path, query.Encode())
+	}
+
+	if p.CursorMode {
+		if hasNext {
+			result.Next = pageURL(map[string]int64{"cursor": lastID})
+		}
+		return result
+	}
+
+	if hasNext {
+		result.Next = pageURL(map[string]int64{"offset": p.Offset + p.Limit})
+	}
+	if p.Offset > 0 {
+		previous := p.Offset - p.Limit
+		if previous < 0 {
+			previous = 0
+		}
+		result.Previous = pageURL(map[string]int64{"offset": previous})
+	}
+	return result
+}
+
+// parseSort parses sort_by parameter in the form field:asc or field:desc, the direction
+// is optional and defaults to ascending. Only fields from the allow-list and lowercase
+// directions are accepted, as documented in the spec.
+func parseSort(r *http.Request, allowed []string) (string, bool, error) {
+	value := r.URL.Query().Get("sort_by")
+	if value == "" {
+		return "", false, nil
+	}
+
+	field, direction, _ := strings.Cut(value, ":")
+	desc := false
+	switch direction {
+	case "", "asc":
+	case "desc":
+		desc = true
+	default:
+		return "", false, fmt.Errorf("%w: sort direction must be asc or desc", ErrInvalidSort)
+	}
+
+	for _, allowedField := range allowed {
+		if field == allowedField {
+			return field, desc, nil
+		}
+	}
+	return "", false, fmt.Errorf("%w: sort_by must be one of %s", ErrInvalidSort, strings.Join(allowed, ", "))
+}
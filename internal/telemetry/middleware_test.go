@@ -0,0 +1,54 @@
+package telemetry_test
+
+import (
+	"consoledot-go-template/internal/logging"
+	"consoledot-go-template/internal/telemetry"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// initializeInMemory sets up the global tracer provider recording all spans into memory
+func initializeInMemory() *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(exporter),
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+	)
+	otel.SetTracerProvider(tp)
+	return exporter
+}
+
+func TestMiddleware(t *testing.T) {
+	exporter := initializeInMemory()
+
+	router := chi.NewRouter()
+	router.Use(telemetry.Middleware)
+	router.Get("/hellos/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, span := telemetry.StartSpan(r.Context(), "GetHello")
+		defer span.End()
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", logging.TraceID(r.Context()))
+	})
+
+	req, err := http.NewRequestWithContext(context.Background(), "GET", "/hellos/42", nil)
+	require.NoError(t, err, "failed to create request")
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	require.Equal(t, 2, len(spans))
+	assert.Equal(t, "GetHello", spans[0].Name)
+	assert.Equal(t, "GET /hellos/{id}", spans[1].Name)
+	assert.Equal(t, trace.SpanKindServer, spans[1].SpanKind)
+	assert.Equal(t, "00f067aa0ba902b7", spans[1].Parent.SpanID().String())
+	assert.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+}
@@ -0,0 +1,68 @@
+package telemetry
+
+import (
+	"consoledot-go-template/internal/config"
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation name of all spans created by the service
+const TracerName = "consoledot-go-template"
+
+func init() {
+	// propagate W3C trace context even when tracing is disabled
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+}
+
+// Initialize sets up the global tracer provider exporting spans to the OTLP collector.
+// When tracing is disabled, the global no-op provider is kept. It returns a close function
+// which flushes all spans.
+func Initialize(ctx context.Context) (func(), error) {
+	if !config.Telemetry.Enabled {
+		return func() {}, nil
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(config.Telemetry.Endpoint)}
+	if config.Telemetry.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create OTLP exporter: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(newResource()),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.Telemetry.SamplerRatio))),
+	)
+	otel.SetTracerProvider(tp)
+
+	return func() {
+		if err := tp.Shutdown(context.Background()); err != nil {
+			log.Logger.Warn().Err(err).Msg("Unable to flush traces")
+		}
+	}, nil
+}
+
+func newResource() *resource.Resource {
+	name := config.Telemetry.ServiceName
+	if name == "" {
+		name = config.BinaryName()
+	}
+	return resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(name))
+}
+
+// StartSpan starts a new internal span, the caller must end the returned span.
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, opts...)
+}
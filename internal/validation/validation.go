@@ -0,0 +1,212 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// TagName is the struct tag holding comma separated validation rules,
+// e.g. `validate:"required,max=100,email"`. Supported rules are:
+//
+//   - required: the value must not be empty (blank strings are empty)
+//   - min=N, max=N: length of strings (in characters) and slices or value of numbers
+//   - email: the string must be a plain email address, empty strings are not checked
+const TagName = "validate"
+
+var ErrValidation = errors.New("validation failed")
+
+// Rule is a single constraint parsed from the validation tag
+type Rule struct {
+	Name  string
+	Param string
+}
+
+// FieldError describes a constraint violation of a single field
+type FieldError struct {
+	// JSON path of the field, e.g. "sender" or "items[0].name"
+	Field string `json:"field"`
+	// user facing description of the violation
+	Message string `json:"message"`
+}
+
+// Errors holds all violations found in a payload
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fmt.Sprintf("%s: %s", fe.Field, fe.Message)
+	}
+	return fmt.Sprintf("%s: %s", ErrValidation.Error(), strings.Join(messages, "; "))
+}
+
+func (e Errors) Unwrap() error {
+	return ErrValidation
+}
+
+// ParseTag parses the validation tag value into rules
+func ParseTag(tag string) []Rule {
+	if tag == "" {
+		return nil
+	}
+	parts := strings.Split(tag, ",")
+	rules := make([]Rule, 0, len(parts))
+	for _, part := range parts {
+		name, param, _ := strings.Cut(strings.TrimSpace(part), "=")
+		rules = append(rules, Rule{Name: name, Param: param})
+	}
+	return rules
+}
+
+// JSONName returns the name of the field in JSON and false when the field
+// is not serialized.
+func JSONName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, true
+}
+
+// IsFlattened returns true for embedded structs which fields are serialized as fields
+// of the parent struct.
+func IsFlattened(field reflect.StructField) bool {
+	if !field.Anonymous || field.Tag.Get("json") != "" {
+		return false
+	}
+	t := field.Type
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// Validate checks all fields of the struct (and nested structs and slices) against
+// rules in their validation tags. It returns Errors with all violations or nil.
+// It panics on unknown rules or invalid rule parameters as these are programming errors.
+func Validate(v interface{}) error {
+	var result Errors
+	validateValue(reflect.ValueOf(v), "", &result)
+	if len(result) > 0 {
+		return result
+	}
+	return nil
+}
+
+func validateValue(value reflect.Value, path string, result *Errors) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		validateStruct(value, path, result)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			validateValue(value.Index(i), fmt.Sprintf("%s[%d]", path, i), result)
+		}
+	default:
+	}
+}
+
+func validateStruct(value reflect.Value, path string, result *Errors) {
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		// embedded structs without JSON name are flattened by encoding/json
+		if IsFlattened(field) {
+			validateValue(value.Field(i), path, result)
+			continue
+		}
+		name, ok := JSONName(field)
+		if !ok {
+			continue
+		}
+
+		fieldPath := name
+		if path != "" {
+			fieldPath = fmt.Sprintf("%s.%s", path, name)
+		}
+		for _, rule := range ParseTag(field.Tag.Get(TagName)) {
+			if message := checkRule(rule, value.Field(i)); message != "" {
+				*result = append(*result, FieldError{Field: fieldPath, Message: message})
+			}
+		}
+		validateValue(value.Field(i), fieldPath, result)
+	}
+}
+
+// checkRule returns violation message or an empty string
+func checkRule(rule Rule, value reflect.Value) string {
+	switch rule.Name {
+	case "required":
+		if (value.Kind() == reflect.String && strings.TrimSpace(value.String()) == "") || value.IsZero() {
+			return "is required"
+		}
+	case "min":
+		if size, ok := measure(value); ok && size < ruleParam(rule) {
+			return fmt.Sprintf("must be at least %s", rule.Param)
+		}
+	case "max":
+		if size, ok := measure(value); ok && size > ruleParam(rule) {
+			return fmt.Sprintf("must be at most %s", rule.Param)
+		}
+	case "email":
+		if value.Kind() != reflect.String || value.String() == "" {
+			return ""
+		}
+		if !IsEmail(value.String()) {
+			return "must be a valid email address"
+		}
+	default:
+		panic(fmt.Sprintf("unknown validation rule %q", rule.Name))
+	}
+	return ""
+}
+
+// IsEmail reports whether the value is a plain email address without a display name
+func IsEmail(value string) bool {
+	address, err := mail.ParseAddress(value)
+	return err == nil && address.Address == value
+}
+
+// measure returns the length of strings and collections or the value of numbers
+func measure(value reflect.Value) (float64, bool) {
+	switch value.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(value.Len()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+func ruleParam(rule Rule) float64 {
+	param, err := strconv.ParseFloat(rule.Param, 64)
+	if err != nil {
+		panic(fmt.Sprintf("invalid parameter of validation rule %q: %s", rule.Name, rule.Param))
+	}
+	return param
+}
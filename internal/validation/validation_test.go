@@ -0,0 +1,59 @@
+package validation_test
+
+import (
+	"consoledot-go-template/internal/validation"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type embedded struct {
+	Name string `json:"name" validate:"required,max=5"`
+}
+
+type item struct {
+	Count int `json:"count" validate:"min=1,max=10"`
+}
+
+type payload struct {
+	embedded
+	Email  string `json:"email" validate:"email"`
+	Items  []item `json:"items" validate:"max=2"`
+	Nested *item  `json:"nested"`
+	Hidden string `json:"-" validate:"required"`
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("accepts valid payload", func(t *testing.T) {
+		err := validation.Validate(&payload{embedded: embedded{Name: "hi"}, Email: "a@example.com", Items: []item{{Count: 1}}})
+		assert.NoError(t, err)
+	})
+
+	t.Run("reports all violations with JSON paths", func(t *testing.T) {
+		err := validation.Validate(&payload{
+			embedded: embedded{Name: "  "},
+			Email:    "Name <a@example.com>",
+			Items:    []item{{Count: 1}, {Count: 0}, {Count: 11}},
+			Nested:   &item{Count: 0},
+		})
+		require.ErrorIs(t, err, validation.ErrValidation)
+
+		var fieldErrors validation.Errors
+		require.ErrorAs(t, err, &fieldErrors)
+		assert.Equal(t, validation.Errors{
+			{Field: "name", Message: "is required"},
+			{Field: "email", Message: "must be a valid email address"},
+			{Field: "items", Message: "must be at most 2"},
+			{Field: "items[1].count", Message: "must be at least 1"},
+			{Field: "items[2].count", Message: "must be at most 10"},
+			{Field: "nested.count", Message: "must be at least 1"},
+		}, fieldErrors)
+	})
+
+	t.Run("counts characters, not bytes", func(t *testing.T) {
+		assert.NoError(t, validation.Validate(&payload{embedded: embedded{Name: "žluťo"}}))
+		assert.Error(t, validation.Validate(&payload{embedded: embedded{Name: strings.Repeat("ž", 6)}}))
+	})
+}
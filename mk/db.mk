@@ -4,6 +4,22 @@ MIGRATION_NAME ?= unnamed
 migrate: ## Run database migration
 	go run ./cmd/migrate
 
+.PHONY: migrate-dry-run
+migrate-dry-run: ## Print pending database migrations and verify they apply cleanly
+	go run ./cmd/migrate up --dry-run
+
+.PHONY: migrate-down
+migrate-down: ## Revert the last database migration
+	go run ./cmd/migrate down
+
+.PHONY: migrate-status
+migrate-status: ## Print applied and pending database migrations
+	go run ./cmd/migrate status
+
+.PHONY: lint-migrations
+lint-migrations: ## Check numbering and statements of database migrations
+	go run ./cmd/migrate lint
+
 .PHONY: generate-migration
 MIGRATION_NAME?=unnamed
 generate-migration: ## Generate new migration file, use MIGRATION_NAME=name
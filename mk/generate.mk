@@ -1,5 +1,27 @@
 ##@ Generate
 
 .PHONY: generate-openapi
-generate-openapi: ## Generate OpenAPI spec
+generate-openapi: ## Generate OpenAPI spec and the Go client
 	go run ./cmd/openapi_spec
+	go run ./cmd/openapi_client
+
+.PHONY: check-openapi
+check-openapi: ## Check the generated OpenAPI spec and the Go client are up to date
+	go run ./cmd/openapi_spec --check
+	go run ./cmd/openapi_client --check
+
+OPENAPI_PREVIOUS_REF?=origin/main
+
+.PHONY: check-openapi-breaking
+check-openapi-breaking: ## Report breaking changes of the OpenAPI spec against OPENAPI_PREVIOUS_REF
+	$(eval PREVIOUS_SPEC := $(shell mktemp --suffix=.json))
+	git show $(OPENAPI_PREVIOUS_REF):api/openapi.gen.json > $(PREVIOUS_SPEC)
+	go run ./cmd/openapi_spec --check --previous $(PREVIOUS_SPEC); status=$$?; rm -f $(PREVIOUS_SPEC); exit $$status
+
+# Swagger UI is taken from the swagger-ui-dist build vendored by github.com/swaggo/files (v2.0.2 ships 5.18.2)
+SWAGGER_UI_FILES_VERSION?=v2.0.2
+
+.PHONY: update-swagger-ui
+update-swagger-ui: ## Update Swagger UI embedded into the API docs page, use SWAGGER_UI_FILES_VERSION=vX.Y.Z
+	$(eval SWAGGER_UI_DIR := $(shell cd $$(mktemp -d) && go mod download -json github.com/swaggo/files/v2@$(SWAGGER_UI_FILES_VERSION) | sed -n 's/.*"Dir": "\(.*\)",/\1/p'))
+	install -m 0644 $(SWAGGER_UI_DIR)/dist/swagger-ui-bundle.js $(SWAGGER_UI_DIR)/dist/swagger-ui.css api/docs/
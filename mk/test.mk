@@ -9,4 +9,5 @@ test: ## Run unit tests
 .PHONY: test-database
 test-database: ## Run integration tests (require database)
 	# "go test pkg1 pkg2" would run tests in parallel causing database locks
+	go test --count=1 -v -tags=database ./internal/db
 	go test --count=1 -v -tags=database ./internal/dao/tests
@@ -0,0 +1,176 @@
+// Code generated by cmd/openapi_client from api/openapi.gen.json. DO NOT EDIT.
+// template-api 1.0.0-dev
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ErrorResponse is the v1.ErrorResponse schema.
+type ErrorResponse struct {
+	Code      string                    `json:"code,omitempty"`
+	Error     string                    `json:"error,omitempty"`
+	Fields    []ErrorResponseFieldsItem `json:"fields,omitempty"`
+	Msg       string                    `json:"msg,omitempty"`
+	RequestID string                    `json:"request_id,omitempty"`
+}
+
+// ErrorResponseFieldsItem is the fields property of ErrorResponse.
+type ErrorResponseFieldsItem struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// HelloListResponse is the v1.HelloListResponse schema.
+type HelloListResponse struct {
+	Data  []HelloResponse        `json:"data,omitempty"`
+	Links HelloListResponseLinks `json:"links,omitempty"`
+	Meta  HelloListResponseMeta  `json:"meta,omitempty"`
+}
+
+// HelloListResponseLinks is the links property of HelloListResponse.
+type HelloListResponseLinks struct {
+	Next     string `json:"next,omitempty"`
+	Previous string `json:"previous,omitempty"`
+}
+
+// HelloListResponseMeta is the meta property of HelloListResponse.
+type HelloListResponseMeta struct {
+	Count int64 `json:"count,omitempty"`
+}
+
+// HelloRequest is the v1.HelloRequest schema.
+type HelloRequest struct {
+	ID      int64  `json:"id,omitempty"`
+	Message string `json:"message"`
+	Sender  string `json:"sender"`
+}
+
+// HelloResponse is the v1.HelloResponse schema.
+type HelloResponse struct {
+	ID        int64  `json:"id,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Recipient string `json:"recipient,omitempty"`
+	Sender    string `json:"sender,omitempty"`
+}
+
+// ProblemResponse is the v1.ProblemResponse schema.
+type ProblemResponse struct {
+	Code      string                      `json:"code,omitempty"`
+	Detail    string                      `json:"detail,omitempty"`
+	Fields    []ProblemResponseFieldsItem `json:"fields,omitempty"`
+	Instance  string                      `json:"instance,omitempty"`
+	RequestID string                      `json:"request_id,omitempty"`
+	Status    int64                       `json:"status,omitempty"`
+	Title     string                      `json:"title,omitempty"`
+	Type      string                      `json:"type,omitempty"`
+}
+
+// ProblemResponseFieldsItem is the fields property of ProblemResponse.
+type ProblemResponseFieldsItem struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// GetGreetingListParams holds query parameters of GetGreetingList, zero values are not sent.
+type GetGreetingListParams struct {
+	// Limit maximum number of greetings on the page
+	Limit int64
+	// Offset number of greetings to skip, cannot be combined with cursor
+	Offset int64
+	// Cursor ID of the last greeting of the previous page (keyset pagination)
+	Cursor int64
+	// Sender only greetings with sender containing the value (case-insensitive)
+	Sender string
+	// Recipient only greetings with recipient containing the value (case-insensitive)
+	Recipient string
+	// Message only greetings with message containing the value (case-insensitive)
+	Message string
+	// SortBy sort order in the form field:asc or field:desc, the direction defaults to asc. Cursor can
+	// only be combined with sorting by id.
+	SortBy string
+}
+
+func (p *GetGreetingListParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+	if p.Limit != 0 {
+		query.Set("limit", fmt.Sprint(p.Limit))
+	}
+	if p.Offset != 0 {
+		query.Set("offset", fmt.Sprint(p.Offset))
+	}
+	if p.Cursor != 0 {
+		query.Set("cursor", fmt.Sprint(p.Cursor))
+	}
+	if p.Sender != "" {
+		query.Set("sender", p.Sender)
+	}
+	if p.Recipient != "" {
+		query.Set("recipient", p.Recipient)
+	}
+	if p.Message != "" {
+		query.Set("message", p.Message)
+	}
+	if p.SortBy != "" {
+		query.Set("sort_by", p.SortBy)
+	}
+	return query
+}
+
+// GetGreetingList returns a page of recorded greetings ordered by ID. Pages are selected either by
+// offset or by a cursor, which is the ID of the last greeting of the previous page.
+// It calls operation getGreetingList (GET /hellos).
+func (c *Client) GetGreetingList(ctx context.Context, params *GetGreetingListParams) (*HelloListResponse, error) {
+	var result HelloListResponse
+	err := c.do(ctx, http.MethodGet, "/hellos", params.values(), nil, http.StatusOK, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SayHi allows recording a greeting allowing to send a sender name and a custom greeting message.
+// It calls operation sayHi (POST /hellos).
+func (c *Client) SayHi(ctx context.Context, body *HelloRequest) (*HelloResponse, error) {
+	var result HelloResponse
+	err := c.do(ctx, http.MethodPost, "/hellos", nil, body, http.StatusCreated, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteGreeting deletes a greeting.
+// It calls operation deleteGreeting (DELETE /hellos/{id}).
+func (c *Client) DeleteGreeting(ctx context.Context, id int64) error {
+	return c.do(ctx, http.MethodDelete, "/hellos/"+pathParam(id), nil, nil, http.StatusNoContent, nil)
+}
+
+// GetGreeting returns a single greeting.
+// It calls operation getGreeting (GET /hellos/{id}).
+func (c *Client) GetGreeting(ctx context.Context, id int64) (*HelloResponse, error) {
+	var result HelloResponse
+	err := c.do(ctx, http.MethodGet, "/hellos/"+pathParam(id), nil, nil, http.StatusOK, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UpdateGreeting updates sender and message of a greeting.
+// It calls operation updateGreeting (PUT /hellos/{id}).
+func (c *Client) UpdateGreeting(ctx context.Context, id int64, body *HelloRequest) (*HelloResponse, error) {
+	var result HelloResponse
+	err := c.do(ctx, http.MethodPut, "/hellos/"+pathParam(id), nil, body, http.StatusOK, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
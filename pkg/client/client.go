@@ -0,0 +1,152 @@
+// Package client is a Go client of the template API. Types and operations are generated
+// from the OpenAPI spec by `make generate-openapi` into client.gen.go, this file holds
+// the hand-written transport.
+//
+//	c := client.New("http://localhost:8000/api/template/v1", client.WithIdentity(header))
+//	hellos, err := c.GetGreetingList(ctx, &client.GetGreetingListParams{Limit: 10})
+//	var apiErr *client.Error
+//	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest { ... }
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// IdentityHeader is the HTTP header with the base64 encoded identity of the caller
+const IdentityHeader = "X-Rh-Identity"
+
+// Client calls operations of the API. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	identity   string
+}
+
+// Option configures the client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests, http.DefaultClient is used by default.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithIdentity sets the identity header sent with every request, it can be overridden
+// per request by ContextWithIdentity.
+func WithIdentity(identity string) Option {
+	return func(c *Client) {
+		c.identity = identity
+	}
+}
+
+// New creates a client of the API at the base URL including the API prefix and version,
+// e.g. http://localhost:8000/api/template/v1
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+type ctxKeyType int
+
+const identityCtxKey ctxKeyType = iota
+
+// ContextWithIdentity returns context with identity header sent with requests made with the context,
+// typically the identity of the request being served by the calling service.
+func ContextWithIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, identityCtxKey, identity)
+}
+
+// Error is returned when the API responds with an unexpected status code. Response holds
+// the decoded error payload, it is nil when the body is not a valid error payload.
+type Error struct {
+	StatusCode int
+	Response   *ErrorResponse
+}
+
+func (e *Error) Error() string {
+	if e.Response == nil {
+		return fmt.Sprintf("api error: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	msg := fmt.Sprintf("api error: %d %s: %s", e.StatusCode, e.Response.Code, e.Response.Msg)
+	if e.Response.Error != "" {
+		msg += ": " + e.Response.Error
+	}
+	if e.Response.RequestID != "" {
+		msg += fmt.Sprintf(" (request_id %s)", e.Response.RequestID)
+	}
+	return msg
+}
+
+func pathParam(value interface{}) string {
+	return url.PathEscape(fmt.Sprint(value))
+}
+
+// do sends the request with the JSON body and decodes the response into the result
+// when the response has the expected status code.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body interface{}, status int, result interface{}) error {
+	reqURL := c.baseURL + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("cannot encode request body: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reqBody)
+	if err != nil {
+		return fmt.Errorf("cannot create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	identity := c.identity
+	if value, ok := ctx.Value(identityCtxKey).(string); ok {
+		identity = value
+	}
+	if identity != "" {
+		req.Header.Set(IdentityHeader, identity)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != status {
+		apiErr := &Error{StatusCode: resp.StatusCode}
+		var errResp ErrorResponse
+		if json.NewDecoder(resp.Body).Decode(&errResp) == nil {
+			apiErr.Response = &errResp
+		}
+		return apiErr
+	}
+
+	if result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return fmt.Errorf("cannot decode response: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package client_test
+
+import (
+	"consoledot-go-template/internal/dao/stub"
+	"consoledot-go-template/internal/routes"
+	"consoledot-go-template/pkg/client"
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubContext serves values from the stub context, so the DAO stubs keep state across requests
+type stubContext struct {
+	context.Context
+	stubs context.Context
+}
+
+func (c stubContext) Value(key interface{}) interface{} {
+	if value := c.Context.Value(key); value != nil {
+		return value
+	}
+	return c.stubs.Value(key)
+}
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	stubs := stub.WithAccountDao(stub.WithHelloDao(context.Background()))
+	router := routes.RootRouter()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		router.ServeHTTP(w, r.WithContext(stubContext{Context: r.Context(), stubs: stubs}))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func identity(orgID string) string {
+	return base64.StdEncoding.EncodeToString([]byte(`{"identity": {"org_id": "` + orgID + `", "type": "User"}}`))
+}
+
+func TestClient(t *testing.T) {
+	server := newServer(t)
+	baseURL := server.URL + routes.PathPrefix() + "/v1"
+	c := client.New(baseURL, client.WithIdentity(identity("org1")))
+	ctx := context.Background()
+
+	t.Run("records and lists greetings", func(t *testing.T) {
+		for _, sender := range []string{"joe@example.com", "jane@example.com"} {
+			hello, err := c.SayHi(ctx, &client.HelloRequest{Sender: sender, Message: "hi"})
+			require.NoError(t, err)
+			assert.Equal(t, sender, hello.Sender)
+			assert.NotZero(t, hello.ID)
+		}
+
+		list, err := c.GetGreetingList(ctx, &client.GetGreetingListParams{Limit: 1, Sender: "jane"})
+		require.NoError(t, err)
+		require.Equal(t, 1, len(list.Data))
+		assert.Equal(t, "jane@example.com", list.Data[0].Sender)
+		assert.Equal(t, int64(1), list.Meta.Count)
+	})
+
+	t.Run("identity from context overrides the default", func(t *testing.T) {
+		list, err := c.GetGreetingList(client.ContextWithIdentity(ctx, identity("org2")), nil)
+		require.NoError(t, err)
+		assert.Empty(t, list.Data)
+	})
+
+	t.Run("decodes error responses", func(t *testing.T) {
+		_, err := c.SayHi(ctx, &client.HelloRequest{Sender: "not an email", Message: "hi"})
+
+		var apiErr *client.Error
+		require.True(t, errors.As(err, &apiErr), "unexpected error %v", err)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+		require.NotNil(t, apiErr.Response)
+		require.Equal(t, 1, len(apiErr.Response.Fields))
+		assert.Equal(t, "sender", apiErr.Response.Fields[0].Field)
+	})
+
+	t.Run("missing identity", func(t *testing.T) {
+		err := client.New(baseURL).DeleteGreeting(ctx, 1)
+
+		var apiErr *client.Error
+		require.True(t, errors.As(err, &apiErr), "unexpected error %v", err)
+		assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	})
+}